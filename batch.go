@@ -0,0 +1,232 @@
+package sexpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParseResult is the outcome of parsing one file in a batch.
+type ParseResult struct {
+	Path  string
+	Sexpr *Sexpr
+	Err   error
+}
+
+// BatchOptions configures ParseFiles and ParseFS. A nil *BatchOptions uses
+// the defaults.
+type BatchOptions struct {
+	// Workers bounds how many files are parsed at once, by default
+	// runtime.GOMAXPROCS(0).
+	Workers int
+	// Parse sets the limits each file is parsed with, see ParseContext.
+	Parse *ParseOptions
+}
+
+func (o *BatchOptions) workers() int {
+	if o == nil || o.Workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Workers
+}
+
+// BatchError aggregates the errors of a batch, one per failed file, sorted
+// by path.
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	lines := []string{}
+	for _, err := range e.Errors {
+		lines = append(lines, err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *BatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// ParseFiles parses the files at paths with a bounded pool of workers and
+// streams a result per file, in completion order, on the returned channel.
+// The channel is closed once every file has been parsed or ctx is done; in
+// the latter case files not yet parsed are skipped, so callers should check
+// ctx.Err, or use Collect, which does.
+func ParseFiles(ctx context.Context, paths []string, opts *BatchOptions) <-chan ParseResult {
+	return parseBatch(ctx, paths, func(p string) (io.ReadCloser, error) {
+		return os.Open(p)
+	}, opts)
+}
+
+// ParseFS is ParseFiles for the files in fsys matching pattern. Patterns
+// are those of path.Match, plus a ** segment that matches any number of
+// directories, e.g. **/*.kicad_mod. A malformed pattern is reported before
+// any file is parsed.
+func ParseFS(ctx context.Context, fsys fs.FS, pattern string, opts *BatchOptions) (<-chan ParseResult, error) {
+	paths, err := globFS(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return parseBatch(ctx, paths, func(p string) (io.ReadCloser, error) {
+		return fsys.Open(p)
+	}, opts), nil
+}
+
+// Collect drains results and returns the parsed sexprs by path. Failed
+// files, including empty ones, and ctx's error when the batch was
+// cancelled, are returned together as a *BatchError.
+func Collect(ctx context.Context, results <-chan ParseResult) (map[string]*Sexpr, error) {
+	sexprs := map[string]*Sexpr{}
+	failed := []ParseResult{}
+	for result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		} else {
+			sexprs[result.Path] = result.Sexpr
+		}
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Path < failed[j].Path })
+	errs := []error{}
+	for _, result := range failed {
+		errs = append(errs, fmt.Errorf("%s: %w", result.Path, result.Err))
+	}
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+	if len(errs) > 0 {
+		return sexprs, &BatchError{Errors: errs}
+	}
+	return sexprs, nil
+}
+
+func parseBatch(ctx context.Context, paths []string, open func(string) (io.ReadCloser, error), opts *BatchOptions) <-chan ParseResult {
+	workers := opts.workers()
+	var parseOpts *ParseOptions
+	if opts != nil {
+		parseOpts = opts.Parse
+	}
+	jobs := make(chan string)
+	results := make(chan ParseResult, workers)
+
+	go func() {
+		defer close(jobs)
+		for _, p := range paths {
+			select {
+			case jobs <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				sexpr, err := parseFile(ctx, p, open, parseOpts)
+				select {
+				case results <- ParseResult{Path: p, Sexpr: sexpr, Err: err}:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func parseFile(ctx context.Context, p string, open func(string) (io.ReadCloser, error), opts *ParseOptions) (*Sexpr, error) {
+	f, err := open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sexpr, err := ParseContext(ctx, f, opts)
+	if err == nil && sexpr == nil {
+		return nil, errors.New("empty document")
+	}
+	return sexpr, err
+}
+
+// globFS returns the regular files in fsys matching pattern, sorted.
+func globFS(fsys fs.FS, pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		return regularFiles(fsys, matches), nil
+	}
+	segments := strings.Split(pattern, "/")
+	for _, seg := range segments {
+		if seg == "**" {
+			continue
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, err
+		}
+	}
+	matches := []string{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && matchSegments(segments, strings.Split(p, "/")) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func regularFiles(fsys fs.FS, paths []string) []string {
+	files := []string{}
+	for _, p := range paths {
+		if info, err := fs.Stat(fsys, p); err == nil && !info.IsDir() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// matchSegments matches a path, split on '/', against pattern segments
+// where ** matches zero or more whole segments.
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
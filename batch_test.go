@@ -0,0 +1,109 @@
+package sexpr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func batchFS(n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for i := 0; i < n; i++ {
+		fsys[fmt.Sprintf("lib%d.pretty/R%d.kicad_mod", i%3, i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("(footprint R%d (layer F.Cu))", i))}
+	}
+	fsys["lib0.pretty/broken.kicad_mod"] = &fstest.MapFile{Data: []byte("(footprint")}
+	fsys["lib1.pretty/nested/deep/C1.kicad_mod"] = &fstest.MapFile{Data: []byte("(footprint C1)")}
+	fsys["README"] = &fstest.MapFile{Data: []byte("(not a footprint)")}
+	return fsys
+}
+
+func TestParseFS(t *testing.T) {
+	ctx := context.Background()
+	results, err := ParseFS(ctx, batchFS(50), "*.pretty/*.kicad_mod", &BatchOptions{Workers: 4})
+	require.NoError(t, err)
+
+	sexprs, err := Collect(ctx, results)
+	require.Equal(t, 50, len(sexprs))
+	require.Equal(t, "R7", sexprs["lib1.pretty/R7.kicad_mod"].Params()[0].String())
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	require.Equal(t, 1, len(batchErr.Errors))
+	require.ErrorContains(t, err, "lib0.pretty/broken.kicad_mod: unexpected EOF at Line 1, Column 11")
+}
+
+func TestParseFSRecursive(t *testing.T) {
+	ctx := context.Background()
+	results, err := ParseFS(ctx, batchFS(6), "**/*.kicad_mod", nil)
+	require.NoError(t, err)
+	paths := []string{}
+	for result := range results {
+		paths = append(paths, result.Path)
+	}
+	require.Equal(t, 8, len(paths))
+	require.Contains(t, paths, "lib1.pretty/nested/deep/C1.kicad_mod")
+
+	results, err = ParseFS(ctx, batchFS(6), "lib1.pretty/**", nil)
+	require.NoError(t, err)
+	sexprs, err := Collect(ctx, results)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(sexprs))
+
+	_, err = ParseFS(ctx, batchFS(1), "**/[", nil)
+	require.ErrorIs(t, err, path.ErrBadPattern)
+
+	_, err = ParseFS(ctx, batchFS(1), "[", nil)
+	require.Error(t, err)
+}
+
+func TestParseFSCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := ParseFS(ctx, batchFS(1000), "*.pretty/*.kicad_mod", &BatchOptions{Workers: 2})
+	require.NoError(t, err)
+
+	<-results
+	cancel()
+	sexprs, err := Collect(ctx, results)
+	require.Less(t, len(sexprs), 1000)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestParseFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{}
+	for i := 0; i < 10; i++ {
+		p := filepath.Join(dir, fmt.Sprintf("%d.kicad_mod", i))
+		require.NoError(t, os.WriteFile(p, []byte(fmt.Sprintf("(footprint F%d)", i)), 0o644))
+		paths = append(paths, p)
+	}
+	paths = append(paths, filepath.Join(dir, "missing.kicad_mod"))
+	empty := filepath.Join(dir, "empty.kicad_mod")
+	require.NoError(t, os.WriteFile(empty, []byte(" \n\t"), 0o644))
+	paths = append(paths, empty)
+
+	ctx := context.Background()
+	sexprs, err := Collect(ctx, ParseFiles(ctx, paths, &BatchOptions{Workers: 3}))
+	require.Equal(t, 10, len(sexprs))
+	require.Equal(t, "F3", sexprs[paths[3]].Params()[0].String())
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorContains(t, err, empty+": empty document")
+	_, ok := sexprs[empty]
+	require.False(t, ok)
+}
+
+func TestParseFSLimits(t *testing.T) {
+	ctx := context.Background()
+	results, err := ParseFS(ctx, batchFS(3), "**/*.kicad_mod", &BatchOptions{Parse: &ParseOptions{MaxDepth: 1}})
+	require.NoError(t, err)
+	sexprs, err := Collect(ctx, results)
+	require.Equal(t, 1, len(sexprs))
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
@@ -0,0 +1,234 @@
+package sexpr
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// CanonicalOptions configures the canonical form. A nil *CanonicalOptions
+// leaves children in document order.
+type CanonicalOptions struct {
+	// Sort orders the child sexprs of every sexpr by name, then by the
+	// value of the first of Keys they have a child for, then by their
+	// canonical form. Atoms keep their positions.
+	Sort bool
+	Keys []string
+}
+
+var numberPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// MarshalCanonical returns a byte representation of s that is the same for
+// any two trees that differ only in layout, quoting or number spelling:
+//
+//   - everything is on one line, params separated by a single space;
+//   - strings are quoted only when they must be, except that strings which
+//     look like numbers keep their quotes;
+//   - unquoted numbers are written in plain decimal without redundant signs
+//     or zeros, e.g. +01.50 is written 1.5 and 1e3 is written 1000, unless
+//     that takes more than 21 padding zeros, when 1e500 stays 1e500 and
+//     0.0125e-30 is written 1.25e-32.
+//
+// Canonicalizing the result again yields the same bytes.
+func (s *Sexpr) MarshalCanonical(opts *CanonicalOptions) []byte {
+	var sb strings.Builder
+	s.canonical(&sb, opts)
+	return []byte(sb.String())
+}
+
+// Digest returns the SHA-256 digest of the canonical form of s.
+func (s *Sexpr) Digest(opts *CanonicalOptions) [sha256.Size]byte {
+	return sha256.Sum256(s.MarshalCanonical(opts))
+}
+
+// Canonicalize rewrites the atoms of s and its descendants to their
+// canonical spelling and, when opts asks for it, sorts their children, so
+// String prints the canonical content in the usual layout.
+func (s *Sexpr) Canonicalize(opts *CanonicalOptions) {
+	for _, param := range s.params {
+		switch pv := param.Value().(type) {
+		case *Sexpr:
+			pv.Canonicalize(opts)
+		case *SexprString:
+			v, quoted := canonicalAtom(pv)
+			if v != pv.value || quoted != pv.quoted {
+				pv.SetValueQuoted(v, quoted)
+			}
+		}
+	}
+	if opts == nil || !opts.Sort {
+		return
+	}
+	slots := []*SexprParam{}
+	children := []*Sexpr{}
+	for _, param := range s.params {
+		if child, ok := param.Value().(*Sexpr); ok {
+			slots = append(slots, param)
+			children = append(children, child)
+		}
+	}
+	keyed := make([]canonicalChild, len(children))
+	for i, child := range children {
+		keyed[i] = newCanonicalChild(child, string(child.MarshalCanonical(opts)), opts)
+	}
+	sortCanonical(keyed)
+	changed := false
+	for i, slot := range slots {
+		if slot.value != keyed[i].sexpr {
+			slot.value = keyed[i].sexpr
+			changed = true
+		}
+	}
+	if changed {
+		s.touch()
+	}
+}
+
+func (s *Sexpr) canonical(sb *strings.Builder, opts *CanonicalOptions) {
+	sb.WriteString("(")
+	sb.WriteString(s.name)
+
+	var children []canonicalChild
+	if opts != nil && opts.Sort {
+		children = []canonicalChild{}
+		for _, param := range s.params {
+			if child, ok := param.Value().(*Sexpr); ok {
+				var csb strings.Builder
+				child.canonical(&csb, opts)
+				children = append(children, newCanonicalChild(child, csb.String(), opts))
+			}
+		}
+		sortCanonical(children)
+	}
+
+	next := 0
+	for _, param := range s.params {
+		sb.WriteString(" ")
+		switch pv := param.Value().(type) {
+		case *Sexpr:
+			if children != nil {
+				sb.WriteString(children[next].form)
+				next += 1
+			} else {
+				pv.canonical(sb, opts)
+			}
+		case *SexprString:
+			v, quoted := canonicalAtom(pv)
+			if quoted {
+				sb.WriteString(`"` + v + `"`)
+			} else {
+				sb.WriteString(v)
+			}
+		}
+	}
+	sb.WriteString(")")
+}
+
+type canonicalChild struct {
+	sexpr  *Sexpr
+	key    string
+	hasKey bool
+	form   string
+}
+
+func newCanonicalChild(s *Sexpr, form string, opts *CanonicalOptions) canonicalChild {
+	c := canonicalChild{sexpr: s, form: form}
+	for _, key := range opts.Keys {
+		if v, ok := pathKeyValue(s, key); ok {
+			c.key = v
+			c.hasKey = true
+			break
+		}
+	}
+	return c
+}
+
+func sortCanonical(children []canonicalChild) {
+	sort.SliceStable(children, func(i, j int) bool {
+		a, b := children[i], children[j]
+		if a.sexpr.name != b.sexpr.name {
+			return a.sexpr.name < b.sexpr.name
+		}
+		if a.hasKey != b.hasKey {
+			return !a.hasKey
+		}
+		if a.key != b.key {
+			return a.key < b.key
+		}
+		return a.form < b.form
+	})
+}
+
+// canonicalAtom returns the canonical spelling of ss and whether it is
+// quoted. Quoted values are kept as they were read, escapes included, so
+// only unquoted values that cannot be written bare are escaped here, in the
+// form a lexer with escapes on reads back.
+func canonicalAtom(ss *SexprString) (string, bool) {
+	v := ss.value
+	if ss.quoted {
+		return v, v == "" || shouldQuote(v) || strings.ContainsAny(v, `"\`) || numberPattern.MatchString(v)
+	}
+	if numberPattern.MatchString(v) {
+		return normalizeNumber(v), false
+	}
+	if v == "" || shouldQuote(v) || strings.HasPrefix(v, `"`) {
+		return Escape(v), true
+	}
+	return v, false
+}
+
+// plainDigits bounds the zeros normalizeNumber pads a number with before
+// writing it with an exponent instead.
+const plainDigits = 21
+
+// normalizeNumber rewrites a number matched by numberPattern in plain
+// decimal, or as a mantissa with a single digit before the point and an
+// exponent when that would need more than plainDigits zeros. It works on
+// the digits, so no precision is lost.
+func normalizeNumber(v string) string {
+	neg := strings.HasPrefix(v, "-")
+	v = strings.TrimLeft(v, "+-")
+	mantissa, exp := v, ""
+	if i := strings.IndexAny(v, "eE"); i != -1 {
+		mantissa, exp = v[:i], v[i+1:]
+	}
+	intPart, fracPart, _ := strings.Cut(mantissa, ".")
+
+	// the value is 0.digits * 10^point
+	digits := strings.TrimLeft(intPart+fracPart, "0")
+	point := new(big.Int)
+	if exp != "" {
+		point.SetString(strings.TrimPrefix(exp, "+"), 10)
+	}
+	// each leading zero dropped moves the point one digit left
+	point.Add(point, big.NewInt(int64(len(digits)-len(fracPart))))
+	digits = strings.TrimRight(digits, "0")
+	if digits == "" {
+		return "0"
+	}
+
+	var result string
+	if point.IsInt64() && point.Int64() >= -plainDigits && point.Int64() <= int64(len(digits)+plainDigits) {
+		p := int(point.Int64())
+		switch {
+		case p <= 0:
+			result = "0." + strings.Repeat("0", -p) + digits
+		case p >= len(digits):
+			result = digits + strings.Repeat("0", p-len(digits))
+		default:
+			result = digits[:p] + "." + digits[p:]
+		}
+	} else {
+		result = digits[:1]
+		if len(digits) > 1 {
+			result += "." + digits[1:]
+		}
+		result += "e" + point.Sub(point, big.NewInt(1)).String()
+	}
+	if neg {
+		result = "-" + result
+	}
+	return result
+}
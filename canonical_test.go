@@ -0,0 +1,126 @@
+package sexpr
+
+import (
+	"bufio"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseString(t *testing.T, input string) *Sexpr {
+	root, err := Parse(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	return root
+}
+
+// parseEscaped is parseString with backslash escapes on, as for KiCad files.
+func parseEscaped(t *testing.T, input string) *Sexpr {
+	lexer := NewLexer(bufio.NewReader(strings.NewReader(input)))
+	lexer.SetEscapes(true)
+	root, err := ParseLexer(lexer)
+	require.NoError(t, err)
+	return root
+}
+
+func TestMarshalCanonical(t *testing.T) {
+	root := parseEscaped(t, `(footprint "R1"
+		(at +01.50 -0 1e2)
+		(value "1.50") (descr "a \"b\"") (layer F.Cu) (empty "")
+		(pad 007 1.250e-2 .5 -0.000 12345678901234567890.10)
+	)`)
+	require.Equal(t, `(footprint R1 (at 1.5 0 100) (value "1.50") (descr "a \"b\"") (layer F.Cu) (empty "") (pad 7 0.0125 0.5 0 12345678901234567890.1))`,
+		string(root.MarshalCanonical(nil)))
+
+	// layout, quoting and number spelling do not matter
+	other := parseEscaped(t, `(footprint R1 (at 1.5 0.0 100) (value "1.50") (descr "a \"b\"") (layer "F.Cu") (empty "")
+		(pad 7 0.0125 0.50 0 12345678901234567890.1000))`)
+	require.Equal(t, root.MarshalCanonical(nil), other.MarshalCanonical(nil))
+	require.Equal(t, root.Digest(nil), other.Digest(nil))
+
+	// the canonical form is a fixed point
+	again := parseEscaped(t, string(root.MarshalCanonical(nil)))
+	require.Equal(t, root.MarshalCanonical(nil), again.MarshalCanonical(nil))
+
+	digest := root.Digest(nil)
+	require.Equal(t, 64, len(hex.EncodeToString(digest[:])))
+	require.NotEqual(t, digest, parseEscaped(t, `(footprint R2)`).Digest(nil))
+}
+
+func TestMarshalCanonicalAtoms(t *testing.T) {
+	// large exponents are kept rather than expanded or clamped
+	require.Equal(t, "(a 1e500)", string(parseString(t, `(a 1e500)`).MarshalCanonical(nil)))
+	require.NotEqual(t, parseString(t, `(a 1e500)`).Digest(nil), parseString(t, `(a 1e600)`).Digest(nil))
+
+	// quotes inside unquoted atoms need no escaping
+	root := parseString(t, `(a x"y b\c)`)
+	require.Equal(t, `(a x"y b\c)`, string(root.MarshalCanonical(nil)))
+
+	// atoms that must be quoted are escaped
+	param, err := NewSexprParam(NewSexprStringQuoted(`"x y\`, false))
+	require.NoError(t, err)
+	require.NoError(t, root.AddParam(0, param))
+	form := string(root.MarshalCanonical(nil))
+	require.Equal(t, `(a "\"x y\\" x"y b\c)`, form)
+	require.Equal(t, form, string(parseEscaped(t, form).MarshalCanonical(nil)))
+}
+
+func TestMarshalCanonicalSorted(t *testing.T) {
+	a := parseString(t, `(kicad_pcb (net 1 b) (net 0 "") hide (footprint x (uuid 2) (at 0 0)) (footprint y (uuid 1)) (footprint (at 1 1)))`)
+	b := parseString(t, `(kicad_pcb (footprint y (uuid 1)) (footprint x (at 0 0) (uuid 2)) hide (net 0 "") (footprint (at 1 1)) (net 1 b))`)
+	opts := &CanonicalOptions{Sort: true, Keys: []string{"tstamp", "uuid"}}
+
+	// atoms keep their positions among the sorted children
+
+	require.NotEqual(t, a.MarshalCanonical(nil), b.MarshalCanonical(nil))
+	require.Equal(t, `(kicad_pcb (footprint (at 1 1)) (footprint y (uuid 1)) hide (footprint x (at 0 0) (uuid 2)) (net 0 "") (net 1 b))`,
+		string(a.MarshalCanonical(opts)))
+	require.Equal(t, a.MarshalCanonical(opts), b.MarshalCanonical(opts))
+	require.Equal(t, a.Digest(opts), b.Digest(opts))
+}
+
+func TestCanonicalize(t *testing.T) {
+	root := parseString(t, `(kicad_pcb (net 1 "b") (net 0 "") (at +1.0 2) (footprint "x y" (uuid "u")))`)
+	want := string(root.MarshalCanonical(&CanonicalOptions{Sort: true}))
+	version := root.Version()
+
+	root.Canonicalize(&CanonicalOptions{Sort: true})
+	require.NotEqual(t, version, root.Version())
+	require.Equal(t, want, string(root.MarshalCanonical(nil)))
+	require.Equal(t, "(kicad_pcb\n\t(at 1 2)\n\t(footprint \"x y\"\n\t\t(uuid u)\n\t)\n\t(net 0 \"\")\n\t(net 1 b)\n)", root.String())
+
+	// sorted children keep their parent
+	require.Same(t, root, root.FindDirectChildByNameExact("net").Parent())
+
+	version = root.Version()
+	root.Canonicalize(&CanonicalOptions{Sort: true})
+	require.Equal(t, version, root.Version())
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	cases := map[string]string{
+		"0":                         "0",
+		"-0":                        "0",
+		"+5":                        "5",
+		"-000.10":                   "-0.1",
+		"1.":                        "1",
+		".25":                       "0.25",
+		"1.5e3":                     "1500",
+		"15E-4":                     "0.0015",
+		"-1e+0":                     "-1",
+		"0e5":                       "0",
+		"100":                       "100",
+		"100.00":                    "100",
+		"1e21":                      "1000000000000000000000",
+		"1e22":                      "1e22",
+		"1e-22":                     "0.0000000000000000000001",
+		"1e500":                     "1e500",
+		"-12.5e-30":                 "-1.25e-29",
+		"0.0e999999999999999999999": "0",
+		"1e999999999999999999999":   "1e999999999999999999999",
+	}
+	for in, want := range cases {
+		require.Equal(t, want, normalizeNumber(in), in)
+	}
+}
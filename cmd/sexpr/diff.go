@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+)
+
+var diffCommand = &command{
+	name:  "diff",
+	usage: "old new",
+	short: "print a unified diff of two files after formatting both",
+}
+
+func init() {
+	diffCommand.run = runDiff
+}
+
+func runDiff(e *env, args []string) int {
+	c := diffCommand
+	fs := c.flags(e)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	texts := []string{}
+	for _, path := range fs.Args() {
+		roots, err := parseInput(e, path)
+		if err != nil {
+			return c.fail(e, err)
+		}
+		texts = append(texts, format(roots))
+	}
+	d := unifiedDiff(fs.Arg(0), fs.Arg(1), texts[0], texts[1])
+	if d == "" {
+		return 0
+	}
+	io.WriteString(e.stdout, d)
+	return 1
+}
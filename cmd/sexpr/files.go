@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+func readInput(e *env, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// parseInput reads and parses every top-level sexpr in path.
+func parseInput(e *env, path string) ([]*sexpr.Sexpr, error) {
+	data, err := readInput(e, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseData(path, data)
+}
+
+// parseData parses data with backslash escapes in quoted strings, as KiCad
+// writes them.
+func parseData(path string, data []byte) ([]*sexpr.Sexpr, error) {
+	lexer := sexpr.NewLexer(bufio.NewReader(bytes.NewReader(data)))
+	lexer.SetEscapes(true)
+	roots, err := sexpr.ParseAllLexer(lexer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return roots, nil
+}
+
+// parseSingle is parseInput for files that must hold exactly one sexpr.
+func parseSingle(e *env, path string) (*sexpr.Sexpr, error) {
+	roots, err := parseInput(e, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) != 1 {
+		return nil, fmt.Errorf("%s: expected one top-level sexpr, found %d", path, len(roots))
+	}
+	return roots[0], nil
+}
+
+// format prints roots the way the library's String does, one per line.
+func format(roots []*sexpr.Sexpr) string {
+	var sb strings.Builder
+	for _, root := range roots {
+		sb.WriteString(root.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// writeOutput writes data to path, or to standard output when path is "-".
+func writeOutput(e *env, path string, data string) error {
+	if path == "-" {
+		_, err := io.WriteString(e.stdout, data)
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(data), info.Mode().Perm())
+}
+
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
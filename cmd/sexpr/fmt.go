@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var fmtCommand = &command{
+	name:  "fmt",
+	usage: "[-l] [-d] [-w] [-check] [-ext list] [files or directories]",
+	short: "reformat files with the library's printer",
+}
+
+// defaultFormatExts are the file name suffixes fmt picks up when walking
+// directories.
+var defaultFormatExts = ".kicad_pcb,.kicad_sch,.kicad_sym,.kicad_mod,.kicad_wks,fp-lib-table,sym-lib-table"
+
+func init() {
+	fmtCommand.run = runFmt
+}
+
+type fmtOptions struct {
+	list  bool
+	diff  bool
+	write bool
+	check bool
+}
+
+func runFmt(e *env, args []string) int {
+	c := fmtCommand
+	flags := c.flags(e)
+	opts := fmtOptions{}
+	flags.BoolVar(&opts.list, "l", false, "list files whose formatting differs, exiting with status 1 if there are any")
+	flags.BoolVar(&opts.diff, "d", false, "print unified diffs of the changes, exiting with status 1 if there are any")
+	flags.BoolVar(&opts.write, "w", false, "write the result back to each file instead of to standard output")
+	flags.BoolVar(&opts.check, "check", false, "only report files that are not formatted, exiting with status 1 if there are any")
+	exts := flags.String("ext", defaultFormatExts, "comma separated file name suffixes to format when walking directories")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	suffixes := splitList(*exts)
+
+	status := 0
+	// record keeps the worst status seen: errors over changes
+	record := func(changed bool, err error) {
+		if err != nil {
+			fmt.Fprintf(e.stderr, "sexpr fmt: %s\n", err.Error())
+			status = 2
+		} else if changed && status == 0 && (opts.list || opts.diff || opts.check) {
+			status = 1
+		}
+	}
+	for _, path := range paths {
+		if path == "-" {
+			changed, err := formatFile(e, path, opts)
+			record(changed, err)
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			record(false, err)
+			continue
+		}
+		if !info.IsDir() {
+			changed, err := formatFile(e, path, opts)
+			record(changed, err)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				record(false, err)
+				return nil
+			}
+			if d.IsDir() {
+				if p != path && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !hasSuffix(d.Name(), suffixes) {
+				return nil
+			}
+			changed, err := formatFile(e, p, opts)
+			record(changed, err)
+			return nil
+		})
+		if err != nil {
+			record(false, err)
+		}
+	}
+	return status
+}
+
+func hasSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFile formats one file as opts ask and reports whether its
+// formatting differs.
+func formatFile(e *env, path string, opts fmtOptions) (bool, error) {
+	data, err := readInput(e, path)
+	if err != nil {
+		return false, err
+	}
+	roots, err := parseData(path, data)
+	if err != nil {
+		return false, err
+	}
+	out := format(roots)
+	changed := out != string(data)
+
+	if opts.check && changed {
+		fmt.Fprintf(e.stdout, "%s: not formatted\n", path)
+	}
+	if opts.list && changed {
+		fmt.Fprintln(e.stdout, path)
+	}
+	if opts.diff && changed {
+		io.WriteString(e.stdout, unifiedDiff(path+".orig", path, string(data), out))
+	}
+	if opts.write && path != "-" {
+		if changed {
+			return true, writeOutput(e, path, out)
+		}
+		return false, nil
+	}
+	if !opts.list && !opts.diff && !opts.check {
+		_, err = io.WriteString(e.stdout, out)
+	}
+	return changed, err
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+
+	"github.com/mlilley/go-sexpr/convert"
+)
+
+var toJSONCommand = &command{
+	name:  "to-json",
+	usage: "[-keyed] [-indent str] file",
+	short: "convert a file to JSON",
+}
+
+var fromJSONCommand = &command{
+	name:  "from-json",
+	usage: "file",
+	short: "convert the lossless JSON encoding back to a sexpr",
+}
+
+func init() {
+	toJSONCommand.run = runToJSON
+	fromJSONCommand.run = runFromJSON
+}
+
+func runToJSON(e *env, args []string) int {
+	c := toJSONCommand
+	fs := c.flags(e)
+	keyed := fs.Bool("keyed", false, "use the friendly keyed encoding, which cannot be converted back")
+	indent := fs.String("indent", "  ", "indentation, or empty for compact output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	root, err := parseSingle(e, fs.Arg(0))
+	if err != nil {
+		return c.fail(e, err)
+	}
+	if *keyed {
+		err = convert.WriteKeyedJSON(e.stdout, root, *indent)
+	} else {
+		err = convert.WriteJSON(e.stdout, root, *indent)
+	}
+	if err != nil {
+		return c.fail(e, err)
+	}
+	return 0
+}
+
+func runFromJSON(e *env, args []string) int {
+	c := fromJSONCommand
+	fs := c.flags(e)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	data, err := readInput(e, fs.Arg(0))
+	if err != nil {
+		return c.fail(e, err)
+	}
+	root, err := convert.FromJSON(data)
+	if err != nil {
+		return c.fail(e, err)
+	}
+	io.WriteString(e.stdout, root.String()+"\n")
+	return 0
+}
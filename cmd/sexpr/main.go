@@ -0,0 +1,96 @@
+// Command sexpr formats, queries and converts s-expression files such as
+// KiCad boards and libraries.
+//
+// Usage:
+//
+//	sexpr <command> [flags] [args]
+//
+// Run "sexpr help" for the list of commands. Files named "-" are read from
+// standard input. Backslashes in quoted strings escape the character that
+// follows, as in KiCad files. Commands exit with status 1 when they find a
+// problem (an unformatted file, a difference, a violation) and 2 on errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+type command struct {
+	name  string
+	usage string
+	short string
+	run   func(e *env, args []string) int
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		fmtCommand,
+		queryCommand,
+		getCommand,
+		setCommand,
+		toJSONCommand,
+		fromJSONCommand,
+		validateCommand,
+		diffCommand,
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return 2
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(e.stdout)
+		return 0
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(e, args[1:])
+		}
+	}
+	fmt.Fprintf(e.stderr, "sexpr: unknown command '%s'\n", args[0])
+	usage(e.stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: sexpr <command> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.short)
+	}
+}
+
+// flags returns a flag set for c that reports errors and usage to e.stderr.
+func (c *command) flags(e *env) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: sexpr %s %s\n", c.name, c.usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// fail reports err for c and returns the error exit status.
+func (c *command) fail(e *env, err error) int {
+	fmt.Fprintf(e.stderr, "sexpr %s: %s\n", c.name, err.Error())
+	return 2
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testInput = `(kicad_pcb (version 20240108) (generator "pcbnew")
+	(footprint "R" (at 1 2) (property "Reference" "R1"))
+	(footprint "C" (at 3 4 90) (property "Reference" "C1"))
+)`
+
+const testFormatted = `(kicad_pcb
+	(version 20240108)
+	(generator "pcbnew")
+	(footprint "R"
+		(at 1 2)
+		(property "Reference" "R1")
+	)
+	(footprint "C"
+		(at 3 4 90)
+		(property "Reference" "C1")
+	)
+)
+`
+
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr strings.Builder
+	status := run(args, &env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr})
+	return status, stdout.String(), stderr.String()
+}
+
+func writeTemp(t *testing.T, name string, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	return path
+}
+
+func TestUsage(t *testing.T) {
+	status, _, stderr := runTest(t, "")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "usage: sexpr <command>")
+
+	status, stdout, _ := runTest(t, "", "help")
+	require.Equal(t, 0, status)
+	require.Contains(t, stdout, "to-json")
+
+	status, _, stderr = runTest(t, "", "nope")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "unknown command 'nope'")
+
+	status, _, stderr = runTest(t, "", "query", "-x")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "usage: sexpr query")
+}
+
+func TestFmt(t *testing.T) {
+	status, stdout, _ := runTest(t, testInput, "fmt")
+	require.Equal(t, 0, status)
+	require.Equal(t, testFormatted, stdout)
+
+	path := writeTemp(t, "board.kicad_pcb", testInput)
+	status, stdout, _ = runTest(t, "", "fmt", "-check", path)
+	require.Equal(t, 1, status)
+	require.Equal(t, path+": not formatted\n", stdout)
+
+	status, stdout, _ = runTest(t, "", "fmt", "-w", path)
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stdout)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, testFormatted, string(data))
+
+	status, _, _ = runTest(t, "", "fmt", "-check", path)
+	require.Equal(t, 0, status)
+
+	// escaped quotes stay inside their string
+	status, stdout, _ = runTest(t, `(gr_text "rev \"A\"" (at 1 2))`, "fmt")
+	require.Equal(t, 0, status)
+	require.Equal(t, "(gr_text \"rev \\\"A\\\"\"\n\t(at 1 2)\n)\n", stdout)
+
+	status, _, stderr := runTest(t, "(a", "fmt")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "sexpr fmt: -: ")
+}
+
+func TestFmtListDiff(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib.pretty"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+	files := map[string]string{
+		"board.kicad_pcb":             testFormatted,
+		"lib.pretty/R.kicad_mod":      "(footprint \"R\" (layer \"F.Cu\"))\n",
+		"lib.pretty/C.kicad_mod":      "(footprint \"C\"\n\t(layer \"F.Cu\")\n)\n",
+		"fp-lib-table":                "(fp_lib_table (version 7))",
+		"notes.txt":                   "(not formatted)",
+		".git/config.kicad_pcb":       "(x (y))",
+		"lib.pretty/broken.kicad_mod": "(footprint",
+	}
+	for name, data := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+
+	status, stdout, stderr := runTest(t, "", "fmt", "-l", dir)
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "broken.kicad_mod: ")
+	require.Equal(t, filepath.Join(dir, "fp-lib-table")+"\n"+filepath.Join(dir, "lib.pretty/R.kicad_mod")+"\n", stdout)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "lib.pretty/broken.kicad_mod")))
+	status, stdout, _ = runTest(t, "", "fmt", "-d", filepath.Join(dir, "lib.pretty"))
+	require.Equal(t, 1, status)
+	path := filepath.Join(dir, "lib.pretty/R.kicad_mod")
+	require.Equal(t, "--- "+path+".orig\n+++ "+path+"\n"+`@@ -1 +1,3 @@
+-(footprint "R" (layer "F.Cu"))
++(footprint "R"
++	(layer "F.Cu")
++)
+`, stdout)
+
+	// a file differing only in its final newline still shows a change
+	status, stdout, _ = runTest(t, "(a b)", "fmt", "-d")
+	require.Equal(t, 1, status)
+	require.Equal(t, "--- -.orig\n+++ -\n@@ -1 +1 @@\n-(a b)\n\\ No newline at end of file\n+(a b)\n", stdout)
+
+	status, stdout, _ = runTest(t, "", "fmt", "-l", "-ext", ".txt", dir)
+	require.Equal(t, 1, status)
+	require.Equal(t, filepath.Join(dir, "notes.txt")+"\n", stdout)
+
+	status, stdout, _ = runTest(t, "", "fmt", "-l", "-w", dir)
+	require.Equal(t, 1, status)
+	require.Equal(t, 2, strings.Count(stdout, "\n"))
+	status, stdout, _ = runTest(t, "", "fmt", "-l", "-d", dir)
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stdout)
+
+	// the walk skips hidden directories, but files named explicitly are formatted
+	status, stdout, _ = runTest(t, "", "fmt", "-l", filepath.Join(dir, ".git/config.kicad_pcb"))
+	require.Equal(t, 1, status)
+	require.Equal(t, filepath.Join(dir, ".git/config.kicad_pcb")+"\n", stdout)
+
+	status, _, stderr = runTest(t, "", "fmt", "-l", filepath.Join(dir, "missing"))
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "no such file")
+}
+
+func TestQuery(t *testing.T) {
+	status, stdout, _ := runTest(t, testInput, "query", "-", "//footprint/at")
+	require.Equal(t, 0, status)
+	require.Equal(t, "(at 1 2)\n(at 3 4 90)\n", stdout)
+
+	status, stdout, _ = runTest(t, testInput, "query", "-paths", "-", "//property")
+	require.Equal(t, 0, status)
+	require.Equal(t, "/kicad_pcb/footprint[0]/property[0]\n/kicad_pcb/footprint[1]/property[0]\n", stdout)
+
+	status, stdout, _ = runTest(t, testInput, "query", "-", "//pad")
+	require.Equal(t, 1, status)
+	require.Equal(t, "", stdout)
+
+	status, _, stderr := runTest(t, testInput, "query", "-", "kicad_pcb")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "must start with '/'")
+}
+
+func TestGet(t *testing.T) {
+	status, stdout, _ := runTest(t, testInput, "get", "-", "//at")
+	require.Equal(t, 0, status)
+	require.Equal(t, "1 2\n3 4 90\n", stdout)
+
+	status, stdout, _ = runTest(t, testInput, "get", "-i", "1", "-", "//property")
+	require.Equal(t, 0, status)
+	require.Equal(t, "R1\nC1\n", stdout)
+
+	status, _, stderr := runTest(t, testInput, "get", "-i", "2", "-", "//at")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "/kicad_pcb/footprint[0]/at[0] has no value at index 2")
+
+	status, _, stderr = runTest(t, testInput, "get", "-", "//pad")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "no match for '//pad'")
+}
+
+func TestSet(t *testing.T) {
+	status, stdout, _ := runTest(t, testInput, "set", "-i", "1", "-", "/kicad_pcb/footprint[1]/property", "C 2")
+	require.Equal(t, 0, status)
+	require.Contains(t, stdout, `(property "Reference" "C 2")`)
+
+	// quoting follows the value replaced
+	status, stdout, _ = runTest(t, testInput, "set", "-i", "1", "-", "//property", "X")
+	require.Equal(t, 0, status)
+	require.Equal(t, 2, strings.Count(stdout, `(property "Reference" "X")`))
+
+	status, stdout, _ = runTest(t, testInput, "set", "-", "//at", "5", "6")
+	require.Equal(t, 0, status)
+	require.Equal(t, 2, strings.Count(stdout, "(at 5 6)"))
+
+	status, stdout, _ = runTest(t, testInput, "set", "-i", "2", "-", "/kicad_pcb/footprint[0]/at", "180")
+	require.Equal(t, 0, status)
+	require.Contains(t, stdout, "(at 1 2 180)")
+
+	status, _, stderr := runTest(t, testInput, "set", "-i", "3", "-", "//at", "1")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "has no value at index 3")
+
+	path := writeTemp(t, "board.kicad_pcb", testInput)
+	status, stdout, _ = runTest(t, "", "set", "-w", path, "/kicad_pcb/version", "20241229")
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stdout)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "(version 20241229)")
+}
+
+func TestSetGetRoundTrip(t *testing.T) {
+	values := []string{`say "hi"`, `C:\dir\file`, "two words", "(paren)", "", "plain"}
+	for _, v := range values {
+		status, stdout, _ := runTest(t, `(a (b x))`, "set", "-", "/a/b", v)
+		require.Equal(t, 0, status, v)
+		status, got, _ := runTest(t, stdout, "get", "-", "/a/b")
+		require.Equal(t, 0, status, v)
+		require.Equal(t, v+"\n", got)
+	}
+
+	status, stdout, _ := runTest(t, `(a (b x))`, "set", "-", "/a/b", `say "hi"`)
+	require.Equal(t, 0, status)
+	require.Equal(t, "(a\n\t(b \"say \\\"hi\\\"\")\n)\n", stdout)
+
+	status, stdout, _ = runTest(t, `(a (b "C:\\dir"))`, "get", "-", "/a/b")
+	require.Equal(t, 0, status)
+	require.Equal(t, "C:\\dir\n", stdout)
+}
+
+func TestJSON(t *testing.T) {
+	status, stdout, _ := runTest(t, `(at 1 "x")`, "to-json", "-indent", "", "-")
+	require.Equal(t, 0, status)
+	require.Equal(t, `{"name":"at","line":1,"column":1,"params":[{"value":"1","quoted":false,"line":1,"column":5},{"value":"x","quoted":true,"line":1,"column":7}]}`+"\n", stdout)
+
+	status, stdout, _ = runTest(t, stdout, "from-json", "-")
+	require.Equal(t, 0, status)
+	require.Equal(t, "(at 1 \"x\")\n", stdout)
+
+	status, stdout, _ = runTest(t, `(a (at 1 2))`, "to-json", "-keyed", "-indent", "", "-")
+	require.Equal(t, 0, status)
+	require.Equal(t, `{"a":{"at":[1,2]}}`+"\n", stdout)
+
+	status, _, stderr := runTest(t, `(a) (b)`, "to-json", "-")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "expected one top-level sexpr, found 2")
+}
+
+func TestValidate(t *testing.T) {
+	status, stdout, _ := runTest(t, "", "validate", "../../kicad/schemas/testdata/8/board.kicad_pcb")
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stdout)
+
+	status, stdout, _ = runTest(t, `(kicad_pcb (version 20240108) (generator "pcbnew") (bogus))`, "validate", "-")
+	require.Equal(t, 1, status)
+	require.Contains(t, stdout, "-:1:52: /kicad_pcb/bogus[0]: unexpected child 'bogus' in 'kicad_pcb'")
+
+	schemaPath := writeTemp(t, "at.schema", `(schema at (root at (param float) (param float)))`)
+	status, stdout, _ = runTest(t, `(at 1 x)`, "validate", "-schema", schemaPath, "-")
+	require.Equal(t, 1, status)
+	require.Contains(t, stdout, "-:1:7: /at: param 1 of 'at': 'x' is not a float")
+
+	status, _, stderr := runTest(t, `(other)`, "validate", "-")
+	require.Equal(t, 2, status)
+	require.Contains(t, stderr, "unknown file kind 'other'")
+}
+
+func TestDiff(t *testing.T) {
+	a := writeTemp(t, "a.kicad_pcb", testInput)
+	b := writeTemp(t, "b.kicad_pcb", testFormatted)
+
+	status, stdout, _ := runTest(t, "", "diff", a, b)
+	require.Equal(t, 0, status)
+	require.Equal(t, "", stdout)
+
+	status, stdout, _ = runTest(t, strings.Replace(testInput, "(at 3 4 90)", "(at 3 4)", 1), "diff", a, "-")
+	require.Equal(t, 1, status)
+	require.Equal(t, "--- "+a+"\n+++ -\n"+`@@ -6,7 +6,7 @@
+ 		(property "Reference" "R1")
+ 	)
+ 	(footprint "C"
+-		(at 3 4 90)
++		(at 3 4)
+ 		(property "Reference" "C1")
+ 	)
+ )
+`, stdout)
+}
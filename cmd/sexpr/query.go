@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+var queryCommand = &command{
+	name:  "query",
+	usage: "[-paths] [-keys key,...] file expr",
+	short: "print the sexprs matched by a path expression",
+}
+
+func init() {
+	queryCommand.run = runQuery
+}
+
+func runQuery(e *env, args []string) int {
+	c := queryCommand
+	fs := c.flags(e)
+	paths := fs.Bool("paths", false, "print the path of each match instead of the match itself")
+	keys := fs.String("keys", "", "comma separated keys to address matches by in -paths output, e.g. uuid,tstamp")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	matches, err := selectInput(e, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return c.fail(e, err)
+	}
+	for _, m := range matches {
+		if *paths {
+			fmt.Fprintln(e.stdout, m.PathKeyed(splitList(*keys)...))
+		} else {
+			fmt.Fprintln(e.stdout, m.String())
+		}
+	}
+	if len(matches) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// selectInput parses path and returns the sexprs matched by expr in each of
+// its top-level sexprs.
+func selectInput(e *env, path string, expr string) ([]*sexpr.Sexpr, error) {
+	roots, err := parseInput(e, path)
+	if err != nil {
+		return nil, err
+	}
+	return selectRoots(roots, expr)
+}
+
+func selectRoots(roots []*sexpr.Sexpr, expr string) ([]*sexpr.Sexpr, error) {
+	matches := []*sexpr.Sexpr{}
+	for _, root := range roots {
+		found, err := sexpr.Select(root, expr)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, found...)
+	}
+	return matches, nil
+}
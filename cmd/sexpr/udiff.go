@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const diffContext = 3
+
+type edit struct {
+	op byte // ' ', '-' or '+'
+	a  int  // line in a, or where an inserted line goes
+	b  int  // line in b, or where a deleted line was
+}
+
+// splitLines splits s after each newline, so a last line without one
+// differs from the same line with one.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// unifiedDiff returns the differences between texts a and b in unified
+// format with three lines of context, or "" when they are equal.
+func unifiedDiff(aName string, bName string, a string, b string) string {
+	if a == b {
+		return ""
+	}
+	al, bl := splitLines(a), splitLines(b)
+	edits := diffLines(al, bl)
+
+	var sb strings.Builder
+	sb.WriteString("--- " + aName + "\n")
+	sb.WriteString("+++ " + bName + "\n")
+	i := 0
+	for i < len(edits) {
+		for i < len(edits) && edits[i].op == ' ' {
+			i += 1
+		}
+		if i == len(edits) {
+			break
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for {
+			for end < len(edits) && edits[end].op != ' ' {
+				end += 1
+			}
+			next := end
+			for next < len(edits) && edits[next].op == ' ' {
+				next += 1
+			}
+			// merge changes separated by less than two contexts
+			if next < len(edits) && next-end <= 2*diffContext {
+				end = next
+				continue
+			}
+			end += diffContext
+			if end > len(edits) {
+				end = len(edits)
+			}
+			break
+		}
+		writeHunk(&sb, al, bl, edits[start:end])
+		i = end
+	}
+	return sb.String()
+}
+
+func writeHunk(sb *strings.Builder, a []string, b []string, hunk []edit) {
+	aCount, bCount := 0, 0
+	for _, e := range hunk {
+		if e.op != '+' {
+			aCount += 1
+		}
+		if e.op != '-' {
+			bCount += 1
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(hunk[0].a, aCount), hunkRange(hunk[0].b, bCount))
+	for _, e := range hunk {
+		switch e.op {
+		case '+':
+			writeLine(sb, "+", b[e.b])
+		case '-':
+			writeLine(sb, "-", a[e.a])
+		default:
+			writeLine(sb, " ", a[e.a])
+		}
+	}
+}
+
+func writeLine(sb *strings.Builder, prefix string, line string) {
+	sb.WriteString(prefix + line)
+	if !strings.HasSuffix(line, "\n") {
+		sb.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// diffLines returns an edit script turning a into b, using the linear
+// space variant of Myers' algorithm: each range is split at the middle
+// snake of its edit path and the halves are diffed in turn. The script is
+// the shortest one unless the ranges differ by more than diffCost edits.
+func diffLines(a []string, b []string) []edit {
+	d := differ{a: a, b: b, edits: []edit{}}
+	d.compare(0, len(a), 0, len(b))
+	// within each run of changes, list deletions before insertions
+	for i := 0; i < len(d.edits); {
+		if d.edits[i].op == ' ' {
+			i += 1
+			continue
+		}
+		j := i
+		for j < len(d.edits) && d.edits[j].op != ' ' {
+			j += 1
+		}
+		run := d.edits[i:j]
+		sort.SliceStable(run, func(x, y int) bool { return run[x].op == '-' && run[y].op == '+' })
+		i = j
+	}
+	return d.edits
+}
+
+type differ struct {
+	a     []string
+	b     []string
+	edits []edit
+}
+
+func (d *differ) compare(aLo int, aHi int, bLo int, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.edits = append(d.edits, edit{op: ' ', a: aLo, b: bLo})
+		aLo += 1
+		bLo += 1
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-suffix-1] == d.b[bHi-suffix-1] {
+		suffix += 1
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi || bLo == bHi || !d.shared(aLo, aHi, bLo, bHi):
+		d.replace(aLo, aHi, bLo, bHi)
+	default:
+		x, y := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		d.compare(x, aHi, y, bHi)
+	}
+
+	for i := 0; i < suffix; i++ {
+		d.edits = append(d.edits, edit{op: ' ', a: aHi + i, b: bHi + i})
+	}
+}
+
+// replace deletes a[aLo:aHi] and inserts b[bLo:bHi] in its place.
+func (d *differ) replace(aLo int, aHi int, bLo int, bHi int) {
+	for x := aLo; x < aHi; x++ {
+		d.edits = append(d.edits, edit{op: '-', a: x, b: bLo})
+	}
+	for y := bLo; y < bHi; y++ {
+		d.edits = append(d.edits, edit{op: '+', a: aHi, b: y})
+	}
+}
+
+// shared reports whether the ranges have a line in common. Ranges without
+// one, such as a file whose every line was reindented, are a plain
+// replacement and need no search.
+func (d *differ) shared(aLo int, aHi int, bLo int, bHi int) bool {
+	lines := map[string]bool{}
+	for _, line := range d.a[aLo:aHi] {
+		lines[line] = true
+	}
+	for _, line := range d.b[bLo:bHi] {
+		if lines[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// diffCost bounds the edit distance middleSnake searches for before it
+// settles for the furthest point reached. Past it the script may be longer
+// than the shortest one, but the diff of a large file that changed
+// throughout still takes time linear in its length rather than quadratic.
+const diffCost = 1024
+
+// middleSnake returns a point on a shortest edit path through the ranges,
+// strictly inside them, found by searching forwards from the start and
+// backwards from the end until the two searches meet. The ranges must
+// differ in their first and last lines.
+func (d *differ) middleSnake(aLo int, aHi int, bLo int, bHi int) (int, int) {
+	a, b := d.a[aLo:aHi], d.b[bLo:bHi]
+	n, m := len(a), len(b)
+	max := (n + m + 1) / 2
+	off := max
+	// forward[off+k] is the furthest x reached on diagonal k = x-y, and
+	// backward[off+k] the same counted from the ends of a and b; -1 marks
+	// diagonals not reached yet
+	forward := make([]int, 2*max)
+	backward := make([]int, 2*max)
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+	forward[off+1] = 0
+	backward[off+1] = 0
+	delta := n - m
+	odd := delta%2 != 0
+	// diagonals that left the grid are trimmed from each end
+	fStart, fEnd, bStart, bEnd := 0, 0, 0, 0
+	for D := 0; D < max; D++ {
+		for k := -D + fStart; k <= D-fEnd; k += 2 {
+			var x int
+			if k == -D || (k != D && forward[off+k-1] < forward[off+k+1]) {
+				x = forward[off+k+1]
+			} else {
+				x = forward[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x += 1
+				y += 1
+			}
+			forward[off+k] = x
+			if x > n {
+				fEnd += 2
+			} else if y > m {
+				fStart += 2
+			} else if rk := off + delta - k; odd && rk >= 0 && rk < len(backward) && backward[rk] != -1 {
+				if x >= n-backward[rk] {
+					return aLo + x, bLo + y
+				}
+			}
+		}
+		for k := -D + bStart; k <= D-bEnd; k += 2 {
+			var x int
+			if k == -D || (k != D && backward[off+k-1] < backward[off+k+1]) {
+				x = backward[off+k+1]
+			} else {
+				x = backward[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x += 1
+				y += 1
+			}
+			backward[off+k] = x
+			if x > n {
+				bEnd += 2
+			} else if y > m {
+				bStart += 2
+			} else if fk := off + delta - k; !odd && fk >= 0 && fk < len(forward) && forward[fk] != -1 {
+				fx := forward[fk]
+				if fx >= n-x {
+					return aLo + fx, bLo + fx - (fk - off)
+				}
+			}
+		}
+		if D == diffCost {
+			x, y := d.furthest(forward, backward, off, D, n, m)
+			return aLo + x, bLo + y
+		}
+	}
+	return aLo + n/2, bLo + m/2
+}
+
+// furthest returns the point reached by the D'th round of either search
+// that is furthest from where that search started.
+func (d *differ) furthest(forward []int, backward []int, off int, D int, n int, m int) (int, int) {
+	bestX, bestY, best := n/2, m/2, 0
+	for k := -D; k <= D; k += 2 {
+		if x := forward[off+k]; x != -1 && x <= n && x-k >= 0 && x-k <= m && 2*x-k > best {
+			bestX, bestY, best = x, x-k, 2*x-k
+		}
+		if x := backward[off+k]; x != -1 && x <= n && x-k >= 0 && x-k <= m && 2*x-k > best {
+			bestX, bestY, best = n-x, m-(x-k), 2*x-k
+		}
+	}
+	if (bestX == 0 && bestY == 0) || (bestX == n && bestY == m) {
+		return n / 2, m / 2
+	}
+	return bestX, bestY
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lines(n int) []string {
+	result := []string{}
+	for i := 1; i <= n; i++ {
+		result = append(result, strings.Repeat("x", i))
+	}
+	return result
+}
+
+func text(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// apply rebuilds b from a and the edit script.
+func apply(a []string, b []string, edits []edit) []string {
+	out := []string{}
+	ai := 0
+	for _, e := range edits {
+		switch e.op {
+		case ' ':
+			out = append(out, a[ai])
+			ai += 1
+		case '-':
+			ai += 1
+		case '+':
+			out = append(out, b[e.b])
+		}
+	}
+	return out
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := [][2][]string{
+		{{}, {}},
+		{{}, {"a"}},
+		{{"a"}, {}},
+		{{"a", "b", "c", "a", "b", "b", "a"}, {"c", "b", "a", "b", "a", "c"}},
+		{lines(20), append(append([]string{"new"}, lines(20)[2:]...), "end")},
+	}
+	for _, c := range cases {
+		edits := diffLines(c[0], c[1])
+		require.Equal(t, c[1], apply(c[0], c[1], edits))
+	}
+	// the classic example has a shortest script of five edits
+	changes := 0
+	for _, e := range diffLines(cases[3][0], cases[3][1]) {
+		if e.op != ' ' {
+			changes += 1
+		}
+	}
+	require.Equal(t, 5, changes)
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a []string, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else if prev[j+1] > cur[j] {
+				cur[j+1] = prev[j+1]
+			} else {
+				cur[j+1] = cur[j]
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestDiffLinesShortest(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		result := []string{}
+		for i := rng.Intn(30); i > 0; i-- {
+			result = append(result, string(rune('a'+rng.Intn(4))))
+		}
+		return result
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		edits := diffLines(a, b)
+		require.Equal(t, b, apply(a, b, edits))
+		changes := 0
+		for _, e := range edits {
+			if e.op != ' ' {
+				changes += 1
+			}
+		}
+		require.Equal(t, len(a)+len(b)-2*lcs(a, b), changes, "%v %v", a, b)
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	// every line reindented, and every other line changed
+	a, b, c := []string{}, []string{}, []string{}
+	for i := 0; i < 50000; i++ {
+		line := strings.Repeat("  ", i%8) + "(line " + strconv.Itoa(i) + ")"
+		a = append(a, line)
+		b = append(b, strings.ReplaceAll(line, "  ", "\t"))
+		if i%2 == 0 {
+			line = "changed"
+		}
+		c = append(c, line)
+	}
+	start := time.Now()
+	require.Equal(t, b, apply(a, b, diffLines(a, b)))
+	require.Equal(t, c, apply(a, c, diffLines(a, c)))
+	require.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	require.Equal(t, "", unifiedDiff("a", "b", "x\n", "x\n"))
+
+	a := lines(20)
+	b := append([]string{}, a...)
+	b[1] = "changed"
+	b[6] = "also changed"
+	b = append(b[:15], b[16:]...)
+	require.Equal(t, `--- a
++++ b
+@@ -1,10 +1,10 @@
+ x
+-xx
++changed
+ xxx
+ xxxx
+ xxxxx
+ xxxxxx
+-xxxxxxx
++also changed
+ xxxxxxxx
+ xxxxxxxxx
+ xxxxxxxxxx
+@@ -13,7 +13,6 @@
+ xxxxxxxxxxxxx
+ xxxxxxxxxxxxxx
+ xxxxxxxxxxxxxxx
+-xxxxxxxxxxxxxxxx
+ xxxxxxxxxxxxxxxxx
+ xxxxxxxxxxxxxxxxxx
+ xxxxxxxxxxxxxxxxxxx
+`, unifiedDiff("a", "b", text(a), text(b)))
+
+	require.Equal(t, "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+xx\n", unifiedDiff("a", "b", "", text(lines(2))))
+	require.Equal(t, "--- a\n+++ b\n@@ -1 +0,0 @@\n-x\n", unifiedDiff("a", "b", text(lines(1)), ""))
+
+	// a missing final newline is a change of its own
+	require.Equal(t, "--- a\n+++ b\n@@ -1,2 +1,2 @@\n x\n-xx\n\\ No newline at end of file\n+xx\n",
+		unifiedDiff("a", "b", "x\nxx", "x\nxx\n"))
+	require.Equal(t, "--- a\n+++ b\n@@ -1 +1 @@\n-x\n+y\n\\ No newline at end of file\n",
+		unifiedDiff("a", "b", "x\n", "y"))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/schemas"
+	"github.com/mlilley/go-sexpr/schema"
+)
+
+var validateCommand = &command{
+	name:  "validate",
+	usage: "[-schema file] files...",
+	short: "check files against a schema, by default the bundled KiCad one for their version",
+}
+
+func init() {
+	validateCommand.run = runValidate
+}
+
+func runValidate(e *env, args []string) int {
+	c := validateCommand
+	fs := c.flags(e)
+	schemaPath := fs.String("schema", "", "schema file to validate against")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	var s *schema.Schema
+	if *schemaPath != "" {
+		var err error
+		s, err = schema.LoadFile(*schemaPath)
+		if err != nil {
+			return c.fail(e, err)
+		}
+	}
+
+	status := 0
+	for _, path := range fs.Args() {
+		roots, err := parseInput(e, path)
+		if err != nil {
+			return c.fail(e, err)
+		}
+		for _, root := range roots {
+			err := validateRoot(root, s)
+			var verr *schema.ValidationError
+			if errors.As(err, &verr) {
+				for _, v := range verr.Violations {
+					fmt.Fprintf(e.stdout, "%s:%d:%d: %s: %s\n", path, v.Line, v.Column, v.Path, v.Message)
+				}
+				status = 1
+			} else if err != nil {
+				return c.fail(e, fmt.Errorf("%s: %w", path, err))
+			}
+		}
+	}
+	return status
+}
+
+func validateRoot(root *sexpr.Sexpr, s *schema.Schema) error {
+	if s == nil {
+		return schemas.Validate(root)
+	}
+	return schema.Validate(root, s)
+}
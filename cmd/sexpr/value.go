@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+var getCommand = &command{
+	name:  "get",
+	usage: "[-i index] file expr",
+	short: "print the values of the sexprs matched by a path expression",
+}
+
+var setCommand = &command{
+	name:  "set",
+	usage: "[-i index] [-w] file expr value...",
+	short: "change the values of the sexprs matched by a path expression",
+}
+
+func init() {
+	getCommand.run = runGet
+	setCommand.run = runSet
+}
+
+func runGet(e *env, args []string) int {
+	c := getCommand
+	fs := c.flags(e)
+	index := fs.Int("i", -1, "print only the value at this index, counting string params from 0")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	matches, err := selectInput(e, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return c.fail(e, err)
+	}
+	if len(matches) == 0 {
+		return c.fail(e, fmt.Errorf("no match for '%s'", fs.Arg(1)))
+	}
+	for _, m := range matches {
+		atoms := atomsOf(m)
+		if *index < 0 {
+			values := []string{}
+			for _, atom := range atoms {
+				values = append(values, atomValue(atom))
+			}
+			fmt.Fprintln(e.stdout, strings.Join(values, " "))
+			continue
+		}
+		if *index >= len(atoms) {
+			return c.fail(e, fmt.Errorf("%s has no value at index %d", m.Path(), *index))
+		}
+		fmt.Fprintln(e.stdout, atomValue(atoms[*index]))
+	}
+	return 0
+}
+
+func runSet(e *env, args []string) int {
+	c := setCommand
+	fs := c.flags(e)
+	index := fs.Int("i", -1, "set only the value at this index, counting string params from 0")
+	write := fs.Bool("w", false, "write the result back to the file instead of to standard output")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 3 {
+		fs.Usage()
+		return 2
+	}
+	path, expr, values := fs.Arg(0), fs.Arg(1), fs.Args()[2:]
+	if *index >= 0 && len(values) != 1 {
+		return c.fail(e, errors.New("-i takes exactly one value"))
+	}
+	roots, err := parseInput(e, path)
+	if err != nil {
+		return c.fail(e, err)
+	}
+	matches, err := selectRoots(roots, expr)
+	if err != nil {
+		return c.fail(e, err)
+	}
+	if len(matches) == 0 {
+		return c.fail(e, fmt.Errorf("no match for '%s'", expr))
+	}
+	for _, m := range matches {
+		if *index >= 0 {
+			err = setValue(m, *index, values[0])
+		} else {
+			err = setValues(m, values)
+		}
+		if err != nil {
+			return c.fail(e, err)
+		}
+	}
+	out := "-"
+	if *write {
+		out = path
+	}
+	if err := writeOutput(e, out, format(roots)); err != nil {
+		return c.fail(e, err)
+	}
+	return 0
+}
+
+func atomsOf(s *sexpr.Sexpr) []*sexpr.SexprString {
+	atoms := []*sexpr.SexprString{}
+	for _, param := range s.Params() {
+		if ss, ok := param.Value().(*sexpr.SexprString); ok {
+			atoms = append(atoms, ss)
+		}
+	}
+	return atoms
+}
+
+// atomValue returns the value of ss with the escapes parseData keeps in
+// quoted strings undone, so that set takes back what get prints.
+func atomValue(ss *sexpr.SexprString) string {
+	if !ss.Quoted() {
+		return ss.Value()
+	}
+	return sexpr.Unescape(ss.Value())
+}
+
+// newAtom returns v as an atom, quoted and escaped when it is empty, holds
+// whitespace, parens or quotes, or when the atom it replaces was quoted.
+func newAtom(v string, old *sexpr.SexprString) *sexpr.SexprString {
+	quoted := v == "" || sexpr.NewSexprString(v).Quoted() || strings.Contains(v, `"`)
+	if old != nil && old.Quoted() {
+		quoted = true
+	}
+	if !quoted {
+		return sexpr.NewSexprStringQuoted(v, false)
+	}
+	return sexpr.NewSexprStringQuoted(sexpr.Escape(v), true)
+}
+
+// setValue replaces the idx'th string param of s, or appends one when idx
+// is the number of string params.
+func setValue(s *sexpr.Sexpr, idx int, v string) error {
+	n := 0
+	last := -1
+	for i, param := range s.Params() {
+		old, ok := param.Value().(*sexpr.SexprString)
+		if !ok {
+			continue
+		}
+		if n == idx {
+			return param.SetValue(newAtom(v, old))
+		}
+		n += 1
+		last = i
+	}
+	if idx != n {
+		return fmt.Errorf("%s has no value at index %d", s.Path(), idx)
+	}
+	param, _ := sexpr.NewSexprParam(newAtom(v, nil))
+	return s.AddParam(last+1, param)
+}
+
+// setValues replaces all string params of s with values, which go where
+// the string params were, or first when there were none.
+func setValues(s *sexpr.Sexpr, values []string) error {
+	for i, v := range values {
+		if err := setValue(s, i, v); err != nil {
+			return err
+		}
+	}
+	for i := len(s.Params()) - 1; i >= 0 && len(atomsOf(s)) > len(values); i-- {
+		param := s.Params()[i]
+		if _, ok := param.Value().(*sexpr.SexprString); ok {
+			s.RemoveParam(i, param)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package convert maps Sexpr trees to and from other data formats.
+//
+// The lossless JSON encoding keeps everything needed to rebuild the tree:
+// names, params in order, whether each string was quoted and the source
+// locations. The keyed encoding is a friendlier, lossy view for consumers
+// that just want to read values, e.g. (at 1 2) becomes "at": [1, 2].
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// jsonList is the lossless encoding of a *sexpr.Sexpr. Params holds
+// jsonList and jsonString values.
+type jsonList struct {
+	Name   string `json:"name"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	Params []any  `json:"params"`
+}
+
+// jsonString is the lossless encoding of a *sexpr.SexprString.
+type jsonString struct {
+	Value  string `json:"value"`
+	Quoted bool   `json:"quoted"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// ToJSON encodes root losslessly as
+//
+//	{"name": NAME, "line": L, "column": C, "params": [PARAM...]}
+//
+// where each PARAM is either another such object or
+// {"value": V, "quoted": Q, "line": L, "column": C}. Locations are omitted
+// when unknown.
+func ToJSON(root *sexpr.Sexpr) ([]byte, error) {
+	if root == nil {
+		return nil, errors.New("nil sexpr")
+	}
+	return json.Marshal(encodeList(root))
+}
+
+// WriteJSON writes the lossless encoding of root to w, indented with indent
+// when it is not empty.
+func WriteJSON(w io.Writer, root *sexpr.Sexpr, indent string) error {
+	if root == nil {
+		return errors.New("nil sexpr")
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	return enc.Encode(encodeList(root))
+}
+
+func encodeList(s *sexpr.Sexpr) *jsonList {
+	line, col := s.Location()
+	l := jsonList{Name: s.Name(), Line: line, Column: col, Params: []any{}}
+	for _, param := range s.Params() {
+		switch pv := param.Value().(type) {
+		case *sexpr.Sexpr:
+			l.Params = append(l.Params, encodeList(pv))
+		case *sexpr.SexprString:
+			line, col := pv.Location()
+			l.Params = append(l.Params, &jsonString{Value: pv.Value(), Quoted: pv.Quoted(), Line: line, Column: col})
+		}
+	}
+	return &l
+}
+
+// FromJSON decodes the lossless encoding produced by ToJSON back into a
+// tree. Strings without a "quoted" member are quoted only when they need to
+// be.
+func FromJSON(data []byte) (*sexpr.Sexpr, error) {
+	var raw json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	param, err := decodeParam(raw, "$")
+	if err != nil {
+		return nil, err
+	}
+	root, ok := param.(*sexpr.Sexpr)
+	if !ok {
+		return nil, errors.New("$: expected a list object with a 'name' member")
+	}
+	return root, nil
+}
+
+func ReadJSON(r io.Reader) (*sexpr.Sexpr, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(data)
+}
+
+// decodeParam decodes a list or string object; path locates it in the
+// document for error messages.
+func decodeParam(raw json.RawMessage, path string) (any, error) {
+	var obj struct {
+		Name   *string           `json:"name"`
+		Value  *string           `json:"value"`
+		Quoted *bool             `json:"quoted"`
+		Line   int               `json:"line"`
+		Column int               `json:"column"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	switch {
+	case obj.Name != nil && obj.Value != nil:
+		return nil, fmt.Errorf("%s: object has both 'name' and 'value'", path)
+	case obj.Value != nil:
+		if obj.Params != nil {
+			return nil, fmt.Errorf("%s: string object has 'params'", path)
+		}
+		ss := sexpr.NewSexprString(*obj.Value)
+		if obj.Quoted != nil {
+			ss = sexpr.NewSexprStringQuoted(*obj.Value, *obj.Quoted)
+		}
+		ss.SetLocation(obj.Line, obj.Column)
+		return ss, nil
+	case obj.Name != nil:
+		s := sexpr.NewSexpr(*obj.Name)
+		s.SetLocation(obj.Line, obj.Column)
+		for i, p := range obj.Params {
+			v, err := decodeParam(p, fmt.Sprintf("%s.params[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			param, err := sexpr.NewSexprParam(v)
+			if err != nil {
+				return nil, err
+			}
+			s.AddParam(len(s.Params()), param)
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("%s: expected a 'name' or 'value' member", path)
+}
@@ -0,0 +1,78 @@
+package convert
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, input string) *sexpr.Sexpr {
+	root, err := sexpr.Parse(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	return root
+}
+
+func TestToJSON(t *testing.T) {
+	root := parse(t, `(a "b c" (d 1))`)
+	data, err := ToJSON(root)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"a","line":1,"column":1,"params":[
+		{"value":"b c","quoted":true,"line":1,"column":4},
+		{"name":"d","line":1,"column":10,"params":[{"value":"1","quoted":false,"line":1,"column":13}]}
+	]}`, string(data))
+
+	// constructed nodes have no locations
+	s := sexpr.NewSexpr("empty")
+	data, err = ToJSON(s)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"empty","params":[]}`, string(data))
+
+	_, err = ToJSON(nil)
+	require.Error(t, err)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../kicad/pcb/testdata/board.kicad_pcb")
+	require.NoError(t, err)
+	root := parse(t, string(data))
+
+	var sb strings.Builder
+	require.NoError(t, WriteJSON(&sb, root, "  "))
+	back, err := ReadJSON(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	require.Equal(t, root.String(), back.String())
+
+	// locations and quoting survive
+	orig := root.FindChildrenByNameExact("pad", -1)[1]
+	got := back.FindChildrenByNameExact("pad", -1)[1]
+	line, col := orig.Location()
+	gotLine, gotCol := got.Location()
+	require.Equal(t, []int{line, col}, []int{gotLine, gotCol})
+	require.Equal(t, orig.Path(), got.Path())
+}
+
+func TestFromJSON(t *testing.T) {
+	root, err := FromJSON([]byte(`{"name":"a","params":[{"value":"x y"},{"value":"1","quoted":true},{"name":"b"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, "(a \"x y\" \"1\"\n\t(b)\n)", root.String())
+	require.Same(t, root, root.Params()[2].Parent())
+
+	_, err = FromJSON([]byte(`{"value":"x"}`))
+	require.ErrorContains(t, err, "expected a list object")
+
+	_, err = FromJSON([]byte(`{"name":"a","params":[{"name":"b","params":[{}]}]}`))
+	require.ErrorContains(t, err, "$.params[0].params[0]: expected a 'name' or 'value' member")
+
+	_, err = FromJSON([]byte(`{"name":"a","value":"b"}`))
+	require.ErrorContains(t, err, "both 'name' and 'value'")
+
+	_, err = FromJSON([]byte(`{"name":"a","params":[1]}`))
+	require.ErrorContains(t, err, "$.params[0]")
+
+	_, err = FromJSON([]byte(`{"name":"a"} {}`))
+	require.ErrorContains(t, err, "unexpected data")
+}
@@ -0,0 +1,210 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// ParamsKey is the default member that holds the positional atoms of a list
+// that also has children in the keyed encoding.
+const ParamsKey = "_"
+
+// KeyedOptions configures the keyed mapping. The zero value, like a nil
+// *KeyedOptions, gives the defaults described on Keyed.
+type KeyedOptions struct {
+	// Repeated selects how children that share a name are mapped.
+	Repeated RepeatMode
+	// ParamsKey overrides ParamsKey.
+	ParamsKey string
+	// ParamNames names the positional atoms of lists by list name, e.g.
+	// "at": {"x", "y", "angle"} maps (at 1 2) to {"x": 1, "y": 2}. Atoms
+	// beyond the names given stay under the params key.
+	ParamNames map[string][]string
+}
+
+func (o *KeyedOptions) paramsKey() string {
+	if o == nil || o.ParamsKey == "" {
+		return ParamsKey
+	}
+	return o.ParamsKey
+}
+
+func (o *KeyedOptions) repeated() RepeatMode {
+	if o == nil {
+		return RepeatArray
+	}
+	return o.Repeated
+}
+
+func (o *KeyedOptions) paramNames(name string) []string {
+	if o == nil {
+		return nil
+	}
+	return o.ParamNames[name]
+}
+
+// Field is a member of an Object.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Object is a JSON object that keeps its members in document order.
+type Object []Field
+
+func (o Object) Get(key string) (any, bool) {
+	for _, f := range o {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeValue(&buf, f.Key); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := encodeValue(&buf, f.Value); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v any) error {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	// Encode terminates each value with a newline
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+var numberPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// Keyed returns the keyed view of root, a single member Object mapping the
+// root's name to its value. A list maps to:
+//
+//   - true, when it is empty, e.g. (locked);
+//   - its atom, when it has exactly one, e.g. (layer F.Cu) is "F.Cu";
+//   - an array of its atoms, when it has several, e.g. (at 1 2) is [1, 2];
+//   - an Object, when it has children, with one member per child name and
+//     any atoms under ParamsKey.
+//
+// Children that share a name with a sibling are gathered into an array.
+// Unquoted atoms that are valid JSON numbers become json.Number, everything
+// else is a string.
+func Keyed(root *sexpr.Sexpr) Object {
+	return KeyedWithOptions(root, nil)
+}
+
+// KeyedWithOptions is Keyed with the mapping adjusted by opts.
+func KeyedWithOptions(root *sexpr.Sexpr, opts *KeyedOptions) Object {
+	return Object{{Key: root.Name(), Value: keyedValue(root, opts)}}
+}
+
+func keyedValue(s *sexpr.Sexpr, opts *KeyedOptions) any {
+	atoms := []any{}
+	children := []*sexpr.Sexpr{}
+	for _, param := range s.Params() {
+		switch pv := param.Value().(type) {
+		case *sexpr.Sexpr:
+			children = append(children, pv)
+		case *sexpr.SexprString:
+			atoms = append(atoms, keyedAtom(pv))
+		}
+	}
+	names := opts.paramNames(s.Name())
+	if len(children) == 0 && len(names) == 0 {
+		switch len(atoms) {
+		case 0:
+			return true
+		case 1:
+			return atoms[0]
+		}
+		return atoms
+	}
+
+	counts := map[string]int{}
+	for _, child := range children {
+		counts[child.Name()] += 1
+	}
+	obj := Object{}
+	for len(names) > 0 && len(atoms) > 0 {
+		obj = append(obj, Field{Key: names[0], Value: atoms[0]})
+		names = names[1:]
+		atoms = atoms[1:]
+	}
+	if len(atoms) > 0 {
+		obj = append(obj, Field{Key: opts.paramsKey(), Value: atoms})
+	}
+	index := map[string]int{}
+	seen := map[string]int{}
+	for _, child := range children {
+		name := child.Name()
+		v := keyedValue(child, opts)
+		mode := opts.repeated()
+		if mode == RepeatNumbered && counts[name] > 1 {
+			obj = append(obj, Field{Key: name + "[" + strconv.Itoa(seen[name]) + "]", Value: v})
+			seen[name] += 1
+			continue
+		}
+		if counts[name] == 1 && mode != RepeatAlways {
+			obj = append(obj, Field{Key: name, Value: v})
+			continue
+		}
+		if i, ok := index[name]; ok {
+			obj[i].Value = append(obj[i].Value.([]any), v)
+			continue
+		}
+		index[name] = len(obj)
+		obj = append(obj, Field{Key: name, Value: []any{v}})
+	}
+	return obj
+}
+
+func keyedAtom(ss *sexpr.SexprString) any {
+	if !ss.Quoted() && numberPattern.MatchString(ss.Value()) {
+		return json.Number(ss.Value())
+	}
+	return ss.Value()
+}
+
+// ToKeyedJSON encodes the keyed view of root. It cannot be converted back.
+func ToKeyedJSON(root *sexpr.Sexpr) ([]byte, error) {
+	if root == nil {
+		return nil, errors.New("nil sexpr")
+	}
+	return json.Marshal(Keyed(root))
+}
+
+// WriteKeyedJSON writes the keyed view of root to w, indented with indent
+// when it is not empty.
+func WriteKeyedJSON(w io.Writer, root *sexpr.Sexpr, indent string) error {
+	if root == nil {
+		return errors.New("nil sexpr")
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if indent != "" {
+		enc.SetIndent("", indent)
+	}
+	return enc.Encode(Keyed(root))
+}
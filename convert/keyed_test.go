@@ -0,0 +1,51 @@
+package convert
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const keyedInput = `(footprint "R_0603" (locked) (layer F.Cu) (at 1 2.5 -90)
+	(property "Reference" "R1" (at 0 -1.5))
+	(pad "1" smd roundrect (at -0.8 0))
+	(pad "2" smd roundrect (at 0.8 0))
+	(uuid "0001")
+	(version 007))`
+
+func TestKeyed(t *testing.T) {
+	obj := Keyed(parse(t, keyedInput))
+	v, ok := obj.Get("footprint")
+	require.True(t, ok)
+	fp := v.(Object)
+
+	keys := []string{}
+	for _, f := range fp {
+		keys = append(keys, f.Key)
+	}
+	require.Equal(t, []string{ParamsKey, "locked", "layer", "at", "property", "pad", "uuid", "version"}, keys)
+
+	at, _ := fp.Get("at")
+	require.Equal(t, []any{json.Number("1"), json.Number("2.5"), json.Number("-90")}, at)
+	pads, _ := fp.Get("pad")
+	require.Equal(t, 2, len(pads.([]any)))
+	uuid, _ := fp.Get("uuid")
+	require.Equal(t, "0001", uuid)
+	version, _ := fp.Get("version")
+	require.Equal(t, "007", version)
+}
+
+func TestToKeyedJSON(t *testing.T) {
+	data, err := ToKeyedJSON(parse(t, keyedInput))
+	require.NoError(t, err)
+	require.Equal(t, `{"footprint":{"_":["R_0603"],"locked":true,"layer":"F.Cu","at":[1,2.5,-90],`+
+		`"property":{"_":["Reference","R1"],"at":[0,-1.5]},`+
+		`"pad":[{"_":["1","smd","roundrect"],"at":[-0.8,0]},{"_":["2","smd","roundrect"],"at":[0.8,0]}],`+
+		`"uuid":"0001","version":"007"}}`, string(data))
+
+	var sb strings.Builder
+	require.NoError(t, WriteKeyedJSON(&sb, parse(t, `(a (b <x>))`), "  "))
+	require.Equal(t, "{\n  \"a\": {\n    \"b\": \"<x>\"\n  }\n}\n", sb.String())
+}
@@ -0,0 +1,17 @@
+package convert
+
+// RepeatMode selects how the keyed mapping treats children of a list that
+// share a name.
+type RepeatMode int
+
+const (
+	// RepeatArray gathers children that share a name with a sibling into an
+	// array; a child with a unique name maps to its value.
+	RepeatArray RepeatMode = iota
+	// RepeatAlways maps every child to an array, so consumers see the same
+	// shape whether a name occurs once or several times.
+	RepeatAlways
+	// RepeatNumbered gives children that share a name their own members,
+	// keyed name[0], name[1], ... as in Sexpr.Path.
+	RepeatNumbered
+)
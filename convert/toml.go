@@ -0,0 +1,156 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// WriteTOML writes the keyed view of root to w as a TOML document. Objects
+// become [tables], arrays of objects become [[arrays of tables]] and
+// everything else is written inline. The root list is the top-level table.
+func WriteTOML(w io.Writer, root *sexpr.Sexpr, opts *KeyedOptions) error {
+	if root == nil {
+		return errors.New("nil sexpr")
+	}
+	var buf bytes.Buffer
+	writeTOMLTable(&buf, nil, KeyedWithOptions(root, opts), true)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func ToTOML(root *sexpr.Sexpr, opts *KeyedOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteTOML(&buf, root, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeTOMLTable writes the inline members of obj and then its sub tables,
+// since a key after a table header belongs to that table.
+func writeTOMLTable(buf *bytes.Buffer, path []string, obj Object, first bool) {
+	for _, f := range obj {
+		if isTOMLTable(f.Value) || isTOMLTableArray(f.Value) {
+			continue
+		}
+		buf.WriteString(tomlKey(f.Key))
+		buf.WriteString(" = ")
+		writeTOMLValue(buf, f.Value)
+		buf.WriteString("\n")
+		first = false
+	}
+	for _, f := range obj {
+		sub := append(append([]string{}, path...), f.Key)
+		if isTOMLTable(f.Value) {
+			writeTOMLHeader(buf, "[", sub, "]", first)
+			writeTOMLTable(buf, sub, f.Value.(Object), false)
+			first = false
+		} else if isTOMLTableArray(f.Value) {
+			for _, item := range f.Value.([]any) {
+				writeTOMLHeader(buf, "[[", sub, "]]", first)
+				writeTOMLTable(buf, sub, item.(Object), false)
+				first = false
+			}
+		}
+	}
+}
+
+func writeTOMLHeader(buf *bytes.Buffer, open string, path []string, close string, first bool) {
+	if !first {
+		buf.WriteString("\n")
+	}
+	keys := []string{}
+	for _, key := range path {
+		keys = append(keys, tomlKey(key))
+	}
+	buf.WriteString(open + strings.Join(keys, ".") + close + "\n")
+}
+
+func isTOMLTable(v any) bool {
+	_, ok := v.(Object)
+	return ok
+}
+
+func isTOMLTableArray(v any) bool {
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return false
+	}
+	for _, item := range items {
+		if !isTOMLTable(item) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeTOMLValue(buf *bytes.Buffer, v any) {
+	switch vv := v.(type) {
+	case Object:
+		buf.WriteString("{ ")
+		for i, f := range vv {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(tomlKey(f.Key))
+			buf.WriteString(" = ")
+			writeTOMLValue(buf, f.Value)
+		}
+		buf.WriteString(" }")
+	case []any:
+		buf.WriteString("[")
+		for i, item := range vv {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeTOMLValue(buf, item)
+		}
+		buf.WriteString("]")
+	case json.Number:
+		buf.WriteString(vv.String())
+	case bool:
+		buf.WriteString(strconv.FormatBool(vv))
+	case string:
+		buf.WriteString(tomlString(vv))
+	}
+}
+
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func tomlKey(key string) string {
+	if bareKeyPattern.MatchString(key) {
+		return key
+	}
+	return tomlString(key)
+}
+
+func tomlString(v string) string {
+	var sb strings.Builder
+	sb.WriteString(`"`)
+	for _, r := range v {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f:
+			sb.WriteString(`\u` + strconv.FormatInt(int64(r)+0x10000, 16)[1:])
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteString(`"`)
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTOML(t *testing.T) {
+	data, err := ToTOML(parse(t, keyedInput), nil)
+	require.NoError(t, err)
+	require.Equal(t, `[footprint]
+_ = ["R_0603"]
+locked = true
+layer = "F.Cu"
+at = [1, 2.5, -90]
+uuid = "0001"
+version = "007"
+
+[footprint.property]
+_ = ["Reference", "R1"]
+at = [0, -1.5]
+
+[[footprint.pad]]
+_ = ["1", "smd", "roundrect"]
+at = [-0.8, 0]
+
+[[footprint.pad]]
+_ = ["2", "smd", "roundrect"]
+at = [0.8, 0]
+`, string(data))
+}
+
+func TestToTOMLOptions(t *testing.T) {
+	opts := KeyedOptions{
+		Repeated:   RepeatAlways,
+		ParamNames: map[string][]string{"at": {"x", "y"}},
+	}
+	data, err := ToTOML(parse(t, `(rules (version 1) (rule "a\tb" (at 1 2)) (note "x\u0001"))`), &opts)
+	require.NoError(t, err)
+	require.Equal(t, `[rules]
+version = [1]
+note = ["x\\u0001"]
+
+[[rules.rule]]
+_ = ["a\\tb"]
+
+[[rules.rule.at]]
+x = 1
+y = 2
+`, string(data))
+}
@@ -0,0 +1,69 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteYAML writes the keyed view of root to w as a YAML document. Members
+// keep their document order and arrays of atoms are written in flow style,
+// e.g. at: [1, 2].
+func WriteYAML(w io.Writer, root *sexpr.Sexpr, opts *KeyedOptions) error {
+	if root == nil {
+		return errors.New("nil sexpr")
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(KeyedWithOptions(root, opts))); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func ToYAML(root *sexpr.Sexpr, opts *KeyedOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteYAML(&buf, root, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func yamlNode(v any) *yaml.Node {
+	switch vv := v.(type) {
+	case Object:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range vv {
+			n.Content = append(n.Content, yamlNode(f.Key), yamlNode(f.Value))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, item := range vv {
+			child := yamlNode(item)
+			if child.Kind != yaml.ScalarNode {
+				n.Style = 0
+			}
+			n.Content = append(n.Content, child)
+		}
+		return n
+	case json.Number:
+		tag := "!!int"
+		if _, err := vv.Int64(); err != nil {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: vv.String()}
+	case bool:
+		if vv {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "false"}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: vv}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
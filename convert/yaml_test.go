@@ -0,0 +1,72 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestToYAML(t *testing.T) {
+	data, err := ToYAML(parse(t, keyedInput), nil)
+	require.NoError(t, err)
+	require.Equal(t, `footprint:
+  _: [R_0603]
+  locked: true
+  layer: F.Cu
+  at: [1, 2.5, -90]
+  property:
+    _: [Reference, R1]
+    at: [0, -1.5]
+  pad:
+    - _: ["1", smd, roundrect]
+      at: [-0.8, 0]
+    - _: ["2", smd, roundrect]
+      at: [0.8, 0]
+  uuid: "0001"
+  version: "007"
+`, string(data))
+
+	var v map[string]map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &v))
+	require.Equal(t, []any{1, 2.5, -90}, v["footprint"]["at"])
+	require.Equal(t, "007", v["footprint"]["version"])
+}
+
+func TestToYAMLOptions(t *testing.T) {
+	opts := KeyedOptions{
+		Repeated:   RepeatNumbered,
+		ParamsKey:  "args",
+		ParamNames: map[string][]string{"at": {"x", "y"}, "pad": {"number"}},
+	}
+	data, err := ToYAML(parse(t, keyedInput), &opts)
+	require.NoError(t, err)
+	require.Equal(t, `footprint:
+  args: [R_0603]
+  locked: true
+  layer: F.Cu
+  at:
+    x: 1
+    y: 2.5
+    args: [-90]
+  property:
+    args: [Reference, R1]
+    at:
+      x: 0
+      y: -1.5
+  pad[0]:
+    number: "1"
+    args: [smd, roundrect]
+    at:
+      x: -0.8
+      y: 0
+  pad[1]:
+    number: "2"
+    args: [smd, roundrect]
+    at:
+      x: 0.8
+      y: 0
+  uuid: "0001"
+  version: "007"
+`, string(data))
+}
@@ -0,0 +1,194 @@
+package dsn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Resolution is a (resolution UNIT N) entry: coordinates are in units of
+// 1/N UNIT.
+type Resolution struct {
+	Unit  string
+	Value int
+}
+
+type Layer struct {
+	Name string
+	Type string
+}
+
+// Place is a component placement. Side is "front" or "back".
+type Place struct {
+	Ref      string
+	X        float64
+	Y        float64
+	Side     string
+	Rotation float64
+}
+
+// Component groups the placements of one footprint image.
+type Component struct {
+	Image  string
+	Places []Place
+}
+
+// Net lists the pins of a net as REF-PIN strings.
+type Net struct {
+	Name string
+	Pins []string
+}
+
+type Class struct {
+	Name string
+	Nets []string
+}
+
+// Design is a read-only view of a DSN (pcb ...) tree.
+type Design struct {
+	Name       string
+	Resolution Resolution
+	Unit       string
+	Layers     []Layer
+	Components []Component
+	Nets       []Net
+	Classes    []Class
+}
+
+func ReadDesign(root *sexpr.Sexpr) (*Design, error) {
+	if err := checkRoot(root, "pcb"); err != nil {
+		return nil, err
+	}
+	d := Design{
+		Name:       atom(root, 0),
+		Resolution: readResolution(root.FindDirectChildByName("resolution")),
+		Unit:       atom(root.FindDirectChildByName("unit"), 0),
+		Layers:     []Layer{},
+		Components: readComponents(root.FindDirectChildByName("placement")),
+		Nets:       []Net{},
+		Classes:    []Class{},
+	}
+	if structure := root.FindDirectChildByName("structure"); structure != nil {
+		for _, l := range structure.FindDirectChildrenByName("layer") {
+			d.Layers = append(d.Layers, Layer{Name: atom(l, 0), Type: atom(l.FindDirectChildByName("type"), 0)})
+		}
+	}
+	if network := root.FindDirectChildByName("network"); network != nil {
+		for _, n := range network.FindDirectChildrenByName("net") {
+			d.Nets = append(d.Nets, Net{Name: atom(n, 0), Pins: atoms(n.FindDirectChildByName("pins"))})
+		}
+		for _, c := range network.FindDirectChildrenByName("class") {
+			all := atoms(c)
+			class := Class{Nets: []string{}}
+			if len(all) > 0 {
+				class.Name = all[0]
+				for _, n := range all[1:] {
+					// KiCad writes an empty description after the class name
+					if n != "" {
+						class.Nets = append(class.Nets, n)
+					}
+				}
+			}
+			d.Classes = append(d.Classes, class)
+		}
+	}
+	return &d, nil
+}
+
+func (d *Design) Net(name string) *Net {
+	for i := range d.Nets {
+		if d.Nets[i].Name == name {
+			return &d.Nets[i]
+		}
+	}
+	return nil
+}
+
+func (d *Design) Place(ref string) *Place {
+	return findPlace(d.Components, ref)
+}
+
+func findPlace(components []Component, ref string) *Place {
+	for i := range components {
+		for j := range components[i].Places {
+			if components[i].Places[j].Ref == ref {
+				return &components[i].Places[j]
+			}
+		}
+	}
+	return nil
+}
+
+func readResolution(s *sexpr.Sexpr) Resolution {
+	v, _ := strconv.Atoi(atom(s, 1))
+	return Resolution{Unit: atom(s, 0), Value: v}
+}
+
+func readComponents(placement *sexpr.Sexpr) []Component {
+	components := []Component{}
+	if placement == nil {
+		return components
+	}
+	for _, c := range placement.FindDirectChildrenByName("component") {
+		comp := Component{Image: atom(c, 0), Places: []Place{}}
+		for _, p := range c.FindDirectChildrenByName("place") {
+			comp.Places = append(comp.Places, Place{
+				Ref:      atom(p, 0),
+				X:        atomFloat(p, 1),
+				Y:        atomFloat(p, 2),
+				Side:     atom(p, 3),
+				Rotation: atomFloat(p, 4),
+			})
+		}
+		components = append(components, comp)
+	}
+	return components
+}
+
+func checkRoot(root *sexpr.Sexpr, name string) error {
+	if root == nil {
+		return fmt.Errorf("empty document")
+	}
+	if !strings.EqualFold(root.Name(), name) {
+		line, col := root.Location()
+		return fmt.Errorf("unexpected root '%s' at Line %d, Column %d, expected '%s'", root.Name(), line, col, name)
+	}
+	return nil
+}
+
+func atoms(s *sexpr.Sexpr) []string {
+	values := []string{}
+	if s == nil {
+		return values
+	}
+	for _, param := range s.Params() {
+		if ss, ok := param.Value().(*sexpr.SexprString); ok {
+			values = append(values, ss.Value())
+		}
+	}
+	return values
+}
+
+func atom(s *sexpr.Sexpr, idx int) string {
+	values := atoms(s)
+	if idx < 0 || idx >= len(values) {
+		return ""
+	}
+	return values[idx]
+}
+
+func atomFloat(s *sexpr.Sexpr, idx int) float64 {
+	return parseFloat(atom(s, idx))
+}
+
+func parseFloat(v string) float64 {
+	f, _ := strconv.ParseFloat(v, 64)
+	return f
+}
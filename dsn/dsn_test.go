@@ -0,0 +1,127 @@
+package dsn
+
+import (
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStringQuote(t *testing.T) {
+	root, err := Parse(strings.NewReader(`(pcb x
+  (parser (string_quote ') (host_cad 'my "cad"'))
+  (a 'b c' d))`))
+	require.NoError(t, err)
+
+	parser := root.FindDirectChildByName("parser")
+	require.Equal(t, "'", atom(parser.FindDirectChildByName("string_quote"), 0))
+	hostCad := parser.FindDirectChildByName("host_cad").Params()[0].Value().(*sexpr.SexprString)
+	require.True(t, hostCad.Quoted())
+	require.Equal(t, `my "cad"`, hostCad.Value())
+	require.Equal(t, []string{"b c", "d"}, atoms(root.FindDirectChildByName("a")))
+
+	require.Equal(t, `(pcb x
+  (parser
+    (string_quote ')
+    (host_cad 'my "cad"')
+  )
+  (a 'b c' d)
+)`, String(root))
+}
+
+func TestParseHeadless(t *testing.T) {
+	root, err := Parse(strings.NewReader(`(pcb ("a b" c) ((d) e) ())`))
+	require.NoError(t, err)
+	children := root.FindChildren(func(s *sexpr.Sexpr, d int) bool { return s.Name() == "" }, 1)
+	require.Equal(t, 3, len(children))
+	require.Equal(t, []string{"a b", "c"}, atoms(children[0]))
+	require.Equal(t, "d", children[1].Params()[0].Value().(*sexpr.Sexpr).Name())
+	require.Equal(t, 0, len(children[2].Params()))
+
+	require.Equal(t, "(pcb\n  (\"a b\" c)\n  (\n    (d) e\n  )\n  ()\n)", String(root))
+}
+
+func TestParseErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		``:                 "empty document",
+		`(a) (b)`:          "unexpected open at Line 1, Column 5",
+		`(a))`:             "unexpected close",
+		`x`:                "unexpected string",
+		`(a (b)`:           "unexpected EOF",
+		`(a "b)`:           "unterminated quoted string",
+		"(a (string_quote": "unexpected EOF",
+	} {
+		_, err := Parse(strings.NewReader(input))
+		require.ErrorContains(t, err, want, input)
+	}
+}
+
+func TestReadDesign(t *testing.T) {
+	root, err := ParseFile("testdata/board.dsn")
+	require.NoError(t, err)
+	d, err := ReadDesign(root)
+	require.NoError(t, err)
+
+	require.Equal(t, `C:\proj\board.dsn`, d.Name)
+	require.Equal(t, Resolution{Unit: "um", Value: 10}, d.Resolution)
+	require.Equal(t, "um", d.Unit)
+	require.Equal(t, []Layer{{Name: "F.Cu", Type: "signal"}, {Name: "B.Cu", Type: "signal"}}, d.Layers)
+	require.Equal(t, 1, len(d.Components))
+	require.Equal(t, &Place{Ref: "R2", X: 120000, Y: -60000, Side: "back", Rotation: 180}, d.Place("R2"))
+	require.Equal(t, []string{"R1-2", "R2-2"}, d.Net("GND").Pins)
+	require.Equal(t, []Class{{Name: "kicad_default", Nets: []string{"/VIN", "GND"}}}, d.Classes)
+
+	_, err = ReadSession(root)
+	require.ErrorContains(t, err, "unexpected root 'pcb'")
+}
+
+func TestReadSession(t *testing.T) {
+	root, err := ParseFile("testdata/board.ses")
+	require.NoError(t, err)
+	ses, err := ReadSession(root)
+	require.NoError(t, err)
+
+	require.Equal(t, "board.ses", ses.Name)
+	require.Equal(t, "board.dsn", ses.BaseDesign)
+	require.Equal(t, Resolution{Unit: "um", Value: 10}, ses.Resolution)
+	require.Equal(t, 1200000.0, ses.Place("R2").X)
+
+	gnd := ses.Net("GND")
+	require.NotNil(t, gnd)
+	require.Equal(t, 2, len(gnd.Wires))
+	require.Equal(t, Wire{Layer: "B.Cu", Width: 2500, Points: []Point{{1150000, -600000}, {1192125, -600000}}}, gnd.Wires[1])
+	require.Equal(t, []Via{{Padstack: "Via[0-1]_600:300_um", At: Point{1150000, -600000}}}, gnd.Vias)
+}
+
+func TestCaseInsensitiveKeywords(t *testing.T) {
+	root, err := Parse(strings.NewReader(`(PCB board (RESOLUTION MIL 1000)
+  (Placement (COMPONENT dip8 (PLACE U1 100 200 Front 90)))
+  (NETWORK (NET vcc (PINS U1-8))))`))
+	require.NoError(t, err)
+	d, err := ReadDesign(root)
+	require.NoError(t, err)
+	require.Equal(t, Resolution{Unit: "MIL", Value: 1000}, d.Resolution)
+	require.Equal(t, 90.0, d.Place("U1").Rotation)
+	require.Equal(t, []string{"U1-8"}, d.Net("vcc").Pins)
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	for _, path := range []string{"testdata/board.dsn", "testdata/board.ses"} {
+		root, err := ParseFile(path)
+		require.NoError(t, err)
+		out := String(root)
+
+		again, err := Parse(strings.NewReader(out))
+		require.NoError(t, err)
+		require.Equal(t, out, String(again))
+		require.Equal(t, root.String(), again.String())
+	}
+
+	root, err := ParseFile("testdata/board.dsn")
+	require.NoError(t, err)
+	out := String(root)
+	require.True(t, strings.HasPrefix(out, "(pcb \"C:\\proj\\board.dsn\"\n  (parser\n    (string_quote \")\n"))
+	require.Contains(t, out, `(class kicad_default "" /VIN GND`)
+	require.Contains(t, out, "(place R1 110000 -60000 front 0\n        (PN 10k)\n      )")
+}
@@ -0,0 +1,152 @@
+// Package dsn reads and writes Specctra DSN design and SES session files,
+// as exchanged with autorouters such as FreeRouting.
+//
+// Specctra files differ from KiCad's s-expressions in three ways: a
+// (string_quote C) directive changes the quote character for the rest of
+// the file, lists may be headless (their first element is not a keyword),
+// and keywords are case-insensitive. Headless lists are represented as a
+// Sexpr with an empty name; use the case-folding finders (FindChildByName
+// and friends) to look up keywords.
+package dsn
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+type frame struct {
+	sexpr *sexpr.Sexpr
+	// head is true until the list's first element is read.
+	head bool
+}
+
+// Parse reads a single DSN or SES tree. Backslashes in quoted strings are
+// literal, as in Windows paths.
+func Parse(r io.Reader) (*sexpr.Sexpr, error) {
+	lexer := sexpr.NewLexer(bufio.NewReader(r))
+
+	var root *sexpr.Sexpr
+	stack := []*frame{}
+	var token sexpr.Token
+
+	for {
+		lexer.NextToken(&token)
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch token.Kind {
+		case sexpr.TokenWhitespace:
+			// ignore
+
+		case sexpr.TokenOpen:
+			if top == nil && root != nil {
+				return nil, fmt.Errorf("unexpected open at Line %d, Column %d", token.Line, token.Column)
+			}
+			s := sexpr.NewSexpr("")
+			s.SetLocation(token.Line, token.Column)
+			if top != nil {
+				top.head = false
+			} else {
+				root = s
+			}
+			stack = append(stack, &frame{sexpr: s, head: true})
+
+		case sexpr.TokenClose:
+			if top == nil {
+				return nil, fmt.Errorf("unexpected close at Line %d, Column %d", token.Line, token.Column)
+			}
+			stack = stack[:len(stack)-1]
+			// lists are attached once complete, so adding to a list never
+			// bumps the versions of more than its own parent
+			if len(stack) > 0 {
+				if err := addParam(stack[len(stack)-1], top.sexpr); err != nil {
+					return nil, err
+				}
+			}
+
+		case sexpr.TokenString:
+			if top == nil {
+				return nil, fmt.Errorf("unexpected string at Line %d, Column %d: '%s'", token.Line, token.Column, token.Content)
+			}
+			if top.head {
+				top.sexpr.SetName(token.Content)
+				top.head = false
+				if strings.EqualFold(token.Content, "string_quote") {
+					// the directive's argument is the quote character itself
+					lexer.SetQuote(0)
+				}
+				continue
+			}
+			if strings.EqualFold(top.sexpr.Name(), "string_quote") && lexer.Quote() == 0 {
+				q, _ := utf8.DecodeRuneInString(token.Content)
+				lexer.SetQuote(q)
+			}
+			if err := addParam(top, newAtom(token, false)); err != nil {
+				return nil, err
+			}
+
+		case sexpr.TokenQuotedString:
+			if top == nil {
+				return nil, fmt.Errorf("unexpected quoted string at Line %d, Column %d: '%s'", token.Line, token.Column, token.Content)
+			}
+			if err := addParam(top, newAtom(token, true)); err != nil {
+				return nil, err
+			}
+
+		case sexpr.TokenEOF:
+			if top != nil {
+				return nil, fmt.Errorf("unexpected EOF at Line %d, Column %d", token.Line, token.Column)
+			}
+			if root == nil {
+				return nil, fmt.Errorf("empty document")
+			}
+			return root, nil
+
+		case sexpr.TokenErr:
+			return nil, fmt.Errorf("error at Line %d, Column %d: %s", token.Line, token.Column, token.Err.Error())
+		}
+	}
+}
+
+func newAtom(token sexpr.Token, quoted bool) *sexpr.SexprString {
+	v := token.Content
+	if quoted {
+		_, first := utf8.DecodeRuneInString(v)
+		_, last := utf8.DecodeLastRuneInString(v)
+		v = v[first : len(v)-last]
+	}
+	ss := sexpr.NewSexprStringQuoted(v, quoted)
+	ss.SetLocation(token.Line, token.Column)
+	return ss
+}
+
+func addParam(f *frame, v any) error {
+	f.head = false
+	param, err := sexpr.NewSexprParam(v)
+	if err != nil {
+		return err
+	}
+	f.sexpr.AddParam(len(f.sexpr.Params()), param)
+	return nil
+}
+
+func ParseFile(path string) (*sexpr.Sexpr, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return root, nil
+}
@@ -0,0 +1,89 @@
+package dsn
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Wire is a routed (wire (path LAYER WIDTH X Y X Y ...)) segment chain.
+type Wire struct {
+	Layer  string
+	Width  float64
+	Points []Point
+}
+
+type Via struct {
+	Padstack string
+	At       Point
+}
+
+type RoutedNet struct {
+	Name  string
+	Wires []Wire
+	Vias  []Via
+}
+
+// Session is a read-only view of an SES (session ...) tree as written back
+// by an autorouter. Coordinates are in units of Resolution.
+type Session struct {
+	Name       string
+	BaseDesign string
+	Resolution Resolution
+	Components []Component
+	Nets       []RoutedNet
+}
+
+func ReadSession(root *sexpr.Sexpr) (*Session, error) {
+	if err := checkRoot(root, "session"); err != nil {
+		return nil, err
+	}
+	ses := Session{
+		Name:       atom(root, 0),
+		BaseDesign: atom(root.FindDirectChildByName("base_design"), 0),
+		Components: readComponents(root.FindDirectChildByName("placement")),
+		Nets:       []RoutedNet{},
+	}
+	routes := root.FindDirectChildByName("routes")
+	if routes == nil {
+		return &ses, nil
+	}
+	ses.Resolution = readResolution(routes.FindDirectChildByName("resolution"))
+	if networkOut := routes.FindDirectChildByName("network_out"); networkOut != nil {
+		for _, n := range networkOut.FindDirectChildrenByName("net") {
+			ses.Nets = append(ses.Nets, readRoutedNet(n))
+		}
+	}
+	return &ses, nil
+}
+
+func readRoutedNet(s *sexpr.Sexpr) RoutedNet {
+	net := RoutedNet{Name: atom(s, 0), Wires: []Wire{}, Vias: []Via{}}
+	for _, w := range s.FindDirectChildrenByName("wire") {
+		path := w.FindDirectChildByName("path")
+		if path == nil {
+			continue
+		}
+		values := atoms(path)
+		wire := Wire{Layer: atom(path, 0), Width: atomFloat(path, 1), Points: []Point{}}
+		for i := 2; i+1 < len(values); i += 2 {
+			wire.Points = append(wire.Points, Point{X: parseFloat(values[i]), Y: parseFloat(values[i+1])})
+		}
+		net.Wires = append(net.Wires, wire)
+	}
+	for _, v := range s.FindDirectChildrenByName("via") {
+		net.Vias = append(net.Vias, Via{Padstack: atom(v, 0), At: Point{X: atomFloat(v, 1), Y: atomFloat(v, 2)}})
+	}
+	return net
+}
+
+func (ses *Session) Net(name string) *RoutedNet {
+	for i := range ses.Nets {
+		if ses.Nets[i].Name == name {
+			return &ses.Nets[i]
+		}
+	}
+	return nil
+}
+
+func (ses *Session) Place(ref string) *Place {
+	return findPlace(ses.Components, ref)
+}
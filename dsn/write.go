@@ -0,0 +1,103 @@
+package dsn
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+const indentUnit = "  "
+
+type writer struct {
+	sb    strings.Builder
+	quote string
+}
+
+// Write writes root in the layout Specctra tools produce: atoms on the
+// list's line, nested lists indented on their own lines. Quoted strings use
+// the quote character in effect at their position, following any
+// string_quote directive in the tree.
+func Write(w io.Writer, root *sexpr.Sexpr) error {
+	wr := writer{quote: `"`}
+	wr.write(root, 0)
+	wr.sb.WriteString("\n")
+	_, err := io.WriteString(w, wr.sb.String())
+	return err
+}
+
+func WriteFile(path string, root *sexpr.Sexpr) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, root); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// String returns root as Write would write it, without the trailing newline.
+func String(root *sexpr.Sexpr) string {
+	var sb strings.Builder
+	Write(&sb, root)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (w *writer) write(s *sexpr.Sexpr, level int) {
+	indent := strings.Repeat(indentUnit, level)
+	w.sb.WriteString(indent)
+	w.sb.WriteString("(")
+	w.sb.WriteString(s.Name())
+	directive := strings.EqualFold(s.Name(), "string_quote")
+	nested := false
+	for i, param := range s.Params() {
+		switch pv := param.Value().(type) {
+		case *sexpr.Sexpr:
+			w.sb.WriteString("\n")
+			w.write(pv, level+1)
+			nested = true
+		case *sexpr.SexprString:
+			if s.Name() != "" || i > 0 {
+				w.sb.WriteString(" ")
+			}
+			if directive {
+				w.sb.WriteString(pv.Value())
+				w.quote = pv.Value()
+				continue
+			}
+			w.atom(pv)
+		}
+	}
+	if nested {
+		w.sb.WriteString("\n")
+		w.sb.WriteString(indent)
+	}
+	w.sb.WriteString(")")
+}
+
+func (w *writer) atom(ss *sexpr.SexprString) {
+	v := ss.Value()
+	if !ss.Quoted() && !needsQuote(v) || w.quote == "" {
+		w.sb.WriteString(v)
+		return
+	}
+	w.sb.WriteString(w.quote)
+	w.sb.WriteString(v)
+	w.sb.WriteString(w.quote)
+}
+
+func needsQuote(v string) bool {
+	if v == "" {
+		return true
+	}
+	for _, r := range v {
+		if r == '(' || r == ')' || unicode.IsSpace(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+// Package edif reads EDIF 2.0.0 netlists.
+//
+// EDIF keywords are case-insensitive and are matched by folding case, but
+// names are case-sensitive and are compared exactly. Names written as
+// (rename IDENT "original") keep both spellings; references such as
+// cellRef and portRef use the identifier.
+package edif
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Name is an EDIF identifier with the original name it was renamed from,
+// if any.
+type Name struct {
+	Ident    string
+	Original string
+}
+
+type Edif struct {
+	Name      Name
+	Version   string
+	Libraries []*Library
+	Design    *Design
+}
+
+// Design is the (design ...) entry naming the top-level cell.
+type Design struct {
+	Name    Name
+	Cell    string
+	Library string
+}
+
+func Load(r io.Reader) (*Edif, error) {
+	root, err := sexpr.Parse(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, errors.New("empty document")
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Edif, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	e, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return e, nil
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Edif, error) {
+	if !isKeyword(root, "edif") {
+		line, col := root.Location()
+		return nil, fmt.Errorf("unexpected root '%s' at Line %d, Column %d, expected 'edif'", root.Name(), line, col)
+	}
+	e := Edif{
+		Name:      readName(root),
+		Version:   strings.Join(atoms(child(root, "edifVersion")), "."),
+		Libraries: []*Library{},
+	}
+	for _, c := range children(root) {
+		switch {
+		case isKeyword(c, "library"), isKeyword(c, "external"):
+			e.Libraries = append(e.Libraries, newLibrary(c))
+		case isKeyword(c, "design"):
+			cellRef := child(c, "cellRef")
+			e.Design = &Design{
+				Name:    readName(c),
+				Cell:    ident(cellRef),
+				Library: ident(child(cellRef, "libraryRef")),
+			}
+		}
+	}
+	return &e, nil
+}
+
+func (e *Edif) Library(ident string) *Library {
+	for _, l := range e.Libraries {
+		if l.Name.Ident == ident {
+			return l
+		}
+	}
+	return nil
+}
+
+// Cell returns the cell ident in library lib.
+func (e *Edif) Cell(lib string, ident string) *Cell {
+	if l := e.Library(lib); l != nil {
+		return l.Cell(ident)
+	}
+	return nil
+}
+
+// TopCell returns the cell named by the design entry.
+func (e *Edif) TopCell() *Cell {
+	if e.Design == nil {
+		return nil
+	}
+	return e.Cell(e.Design.Library, e.Design.Cell)
+}
+
+// String returns the original name if the identifier was renamed, or the
+// identifier without the '&' EDIF prefixes to names not starting with a
+// letter.
+func (n Name) String() string {
+	if n.Original != "" {
+		return n.Original
+	}
+	return strings.TrimPrefix(n.Ident, "&")
+}
+
+func isKeyword(s *sexpr.Sexpr, keyword string) bool {
+	return s != nil && strings.EqualFold(s.Name(), keyword)
+}
+
+// child returns the first direct child of s with the keyword, or nil. s may
+// be nil.
+func child(s *sexpr.Sexpr, keyword string) *sexpr.Sexpr {
+	if s == nil {
+		return nil
+	}
+	return s.FindDirectChildByName(keyword)
+}
+
+func childrenNamed(s *sexpr.Sexpr, keyword string) []*sexpr.Sexpr {
+	if s == nil {
+		return nil
+	}
+	return s.FindDirectChildrenByName(keyword)
+}
+
+func children(s *sexpr.Sexpr) []*sexpr.Sexpr {
+	found := []*sexpr.Sexpr{}
+	if s == nil {
+		return found
+	}
+	for _, param := range s.Params() {
+		if child, ok := param.Value().(*sexpr.Sexpr); ok {
+			found = append(found, child)
+		}
+	}
+	return found
+}
+
+func atoms(s *sexpr.Sexpr) []string {
+	values := []string{}
+	if s == nil {
+		return values
+	}
+	for _, param := range s.Params() {
+		if ss, ok := param.Value().(*sexpr.SexprString); ok {
+			if ss.Quoted() {
+				values = append(values, decodeString(ss.Value()))
+			} else {
+				values = append(values, ss.Value())
+			}
+		}
+	}
+	return values
+}
+
+// readName reads the name of s, the first param, which is either an
+// identifier or a (rename IDENT "original") or (name IDENT ...) list.
+func readName(s *sexpr.Sexpr) Name {
+	if s == nil || len(s.Params()) == 0 {
+		return Name{}
+	}
+	switch pv := s.Params()[0].Value().(type) {
+	case *sexpr.SexprString:
+		return Name{Ident: pv.Value()}
+	case *sexpr.Sexpr:
+		if isKeyword(pv, "rename") || isKeyword(pv, "name") {
+			values := atoms(pv)
+			n := Name{}
+			if len(values) > 0 {
+				n.Ident = values[0]
+			}
+			if len(values) > 1 {
+				n.Original = values[1]
+			}
+			return n
+		}
+		if isKeyword(pv, "array") {
+			return readName(pv)
+		}
+	}
+	return Name{}
+}
+
+// ident returns the identifier of a reference such as (cellRef IDENT ...).
+func ident(s *sexpr.Sexpr) string {
+	return readName(s).Ident
+}
+
+// decodeString expands %N N ...% escapes, which give characters by their
+// decimal codes.
+func decodeString(v string) string {
+	if !strings.Contains(v, "%") {
+		return v
+	}
+	var sb strings.Builder
+	for {
+		start := strings.Index(v, "%")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(v[start+1:], "%")
+		if end == -1 {
+			break
+		}
+		sb.WriteString(v[:start])
+		for _, code := range strings.Fields(v[start+1 : start+1+end]) {
+			if n, err := strconv.Atoi(code); err == nil {
+				sb.WriteRune(rune(n))
+			}
+		}
+		v = v[start+end+2:]
+	}
+	sb.WriteString(v)
+	return sb.String()
+}
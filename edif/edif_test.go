@@ -0,0 +1,92 @@
+package edif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	e, err := LoadFile("testdata/design.edn")
+	require.NoError(t, err)
+
+	require.Equal(t, Name{Ident: "demo"}, e.Name)
+	require.Equal(t, "2.0.0", e.Version)
+	require.Equal(t, 2, len(e.Libraries))
+	require.True(t, e.Library("GENERIC").External)
+	require.False(t, e.Library("DESIGN").External)
+	require.Equal(t, &Design{Name: Name{Ident: "demo"}, Cell: "top", Library: "DESIGN"}, e.Design)
+
+	res := e.Cell("DESIGN", "RES_0603")
+	require.NotNil(t, res)
+	require.Equal(t, "RES-0603", res.Name.String())
+	require.Equal(t, "GENERIC", res.Type)
+	require.Equal(t, "1", res.View("netlist").Ports[0].Name.String())
+
+	ldo := e.Cell("GENERIC", "LM1117").View("netlist")
+	require.Equal(t, 3, len(ldo.Ports))
+	require.Equal(t, "OUTPUT", ldo.Port("VO").Direction)
+
+	top := e.TopCell()
+	require.NotNil(t, top)
+	require.Equal(t, Name{Ident: "top", Original: "Top Level"}, top.Name)
+	view := top.View("netlist")
+	require.Equal(t, "NETLIST", view.Type)
+	require.Equal(t, &Port{Name: Name{Ident: "D", Original: "D[7:0]"}, Direction: "OUTPUT", Width: 8}, view.Port("D"))
+
+	require.Equal(t, 3, len(view.Instances))
+	r1 := view.Instance("R1")
+	require.Equal(t, &Instance{
+		Name: Name{Ident: "R1"},
+		View: "netlist",
+		Cell: "RES_0603",
+		Properties: []Property{
+			{Name: Name{Ident: "Value"}, Type: "string", Value: "10k"},
+			{Name: Name{Ident: "tol", Original: "Tolerance%"}, Type: "string", Value: "1%"},
+		},
+	}, r1)
+	v, ok := r1.Property("Tolerance%")
+	require.True(t, ok)
+	require.Equal(t, "1%", v)
+	v, _ = view.Instance("r1").Property("Value")
+	require.Equal(t, "4k7", v)
+	u1 := view.Instance("U1")
+	require.Equal(t, "GENERIC", u1.Library)
+	v, _ = u1.Property("Power")
+	require.Equal(t, "25 e -2", v)
+	v, _ = u1.Property("Fitted")
+	require.Equal(t, "true", v)
+
+	require.Equal(t, 3, len(view.Nets))
+	vout := view.Net("VOUT")
+	require.Equal(t, "+3V3", vout.Name.String())
+	require.Equal(t, []PortRef{
+		{Port: "&1", Member: -1, Instance: "R1"},
+		{Port: "&1", Member: -1, Instance: "r1"},
+		{Port: "VO", Member: -1, Instance: "U1"},
+		{Port: "D", Member: 3},
+	}, vout.Joined)
+	require.Equal(t, PortRef{Port: "VIN", Member: -1}, view.Net("VIN").Joined[0])
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(strings.NewReader(""))
+	require.ErrorContains(t, err, "empty document")
+
+	_, err = Load(strings.NewReader("(kicad_pcb)"))
+	require.ErrorContains(t, err, "unexpected root 'kicad_pcb'")
+
+	e, err := Load(strings.NewReader("(EDIF x (LIBRARY lib (CELL c (VIEW v))))"))
+	require.NoError(t, err)
+	view := e.Cell("lib", "c").View("v")
+	require.Equal(t, 0, len(view.Ports))
+	require.Nil(t, e.TopCell())
+}
+
+func TestDecodeString(t *testing.T) {
+	require.Equal(t, "a", decodeString("a"))
+	require.Equal(t, `say "hi"`, decodeString("say %34%hi%34%"))
+	require.Equal(t, "AB!", decodeString("%65 66%!"))
+	require.Equal(t, "100%", decodeString("100%"))
+}
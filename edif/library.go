@@ -0,0 +1,150 @@
+package edif
+
+import (
+	"strconv"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Library is a (library ...) or, with External set, an (external ...)
+// library whose cells are only declared.
+type Library struct {
+	Name     Name
+	External bool
+	Cells    []*Cell
+}
+
+type Cell struct {
+	Name  Name
+	Type  string
+	Views []*View
+}
+
+// View is a cell view: its interface ports and, for netlist views, the
+// instances and nets of its contents.
+type View struct {
+	Name       Name
+	Type       string
+	Ports      []*Port
+	Instances  []*Instance
+	Nets       []*Net
+	Properties []Property
+}
+
+// Port is an interface port. Width is the size of an (array ...) port, or
+// 0 for a scalar one.
+type Port struct {
+	Name      Name
+	Direction string
+	Width     int
+}
+
+func newLibrary(s *sexpr.Sexpr) *Library {
+	l := Library{
+		Name:     readName(s),
+		External: isKeyword(s, "external"),
+		Cells:    []*Cell{},
+	}
+	for _, c := range childrenNamed(s, "cell") {
+		l.Cells = append(l.Cells, newCell(c))
+	}
+	return &l
+}
+
+func newCell(s *sexpr.Sexpr) *Cell {
+	c := Cell{
+		Name:  readName(s),
+		Type:  atom(child(s, "cellType"), 0),
+		Views: []*View{},
+	}
+	for _, v := range childrenNamed(s, "view") {
+		c.Views = append(c.Views, newView(v))
+	}
+	return &c
+}
+
+func newView(s *sexpr.Sexpr) *View {
+	v := View{
+		Name:       readName(s),
+		Type:       atom(child(s, "viewType"), 0),
+		Ports:      []*Port{},
+		Instances:  []*Instance{},
+		Nets:       []*Net{},
+		Properties: readProperties(s),
+	}
+	for _, p := range childrenNamed(child(s, "interface"), "port") {
+		v.Ports = append(v.Ports, newPort(p))
+	}
+	contents := child(s, "contents")
+	for _, i := range childrenNamed(contents, "instance") {
+		v.Instances = append(v.Instances, newInstance(i))
+	}
+	for _, n := range childrenNamed(contents, "net") {
+		v.Nets = append(v.Nets, newNet(n))
+	}
+	return &v
+}
+
+func newPort(s *sexpr.Sexpr) *Port {
+	p := Port{
+		Name:      readName(s),
+		Direction: atom(child(s, "direction"), 0),
+	}
+	if array := child(s, "array"); array != nil {
+		p.Width, _ = strconv.Atoi(atom(array, 0))
+	}
+	return &p
+}
+
+func atom(s *sexpr.Sexpr, idx int) string {
+	values := atoms(s)
+	if idx < 0 || idx >= len(values) {
+		return ""
+	}
+	return values[idx]
+}
+
+func (l *Library) Cell(ident string) *Cell {
+	for _, c := range l.Cells {
+		if c.Name.Ident == ident {
+			return c
+		}
+	}
+	return nil
+}
+
+func (c *Cell) View(ident string) *View {
+	for _, v := range c.Views {
+		if v.Name.Ident == ident {
+			return v
+		}
+	}
+	return nil
+}
+
+func (v *View) Port(ident string) *Port {
+	for _, p := range v.Ports {
+		if p.Name.Ident == ident {
+			return p
+		}
+	}
+	return nil
+}
+
+func (v *View) Instance(ident string) *Instance {
+	for _, i := range v.Instances {
+		if i.Name.Ident == ident {
+			return i
+		}
+	}
+	return nil
+}
+
+func (v *View) Net(ident string) *Net {
+	for _, n := range v.Nets {
+		if n.Name.Ident == ident {
+			return n
+		}
+	}
+	return nil
+}
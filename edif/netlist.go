@@ -0,0 +1,114 @@
+package edif
+
+import (
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Instance is a placed cell. An empty Library means the library the
+// instance itself is in.
+type Instance struct {
+	Name       Name
+	View       string
+	Cell       string
+	Library    string
+	Properties []Property
+}
+
+type Net struct {
+	Name   Name
+	Joined []PortRef
+}
+
+// PortRef is a port on an instance, or on the enclosing cell's interface
+// when Instance is empty. Member is the bit of an array port, or -1.
+type PortRef struct {
+	Port     string
+	Member   int
+	Instance string
+}
+
+// Property is a named value. Value is the value's text: the string or
+// integer as written, or a number's mantissa and exponent as "M e E".
+type Property struct {
+	Name  Name
+	Type  string
+	Value string
+}
+
+func newInstance(s *sexpr.Sexpr) *Instance {
+	viewRef := child(s, "viewRef")
+	cellRef := child(viewRef, "cellRef")
+	return &Instance{
+		Name:       readName(s),
+		View:       ident(viewRef),
+		Cell:       ident(cellRef),
+		Library:    ident(child(cellRef, "libraryRef")),
+		Properties: readProperties(s),
+	}
+}
+
+func newNet(s *sexpr.Sexpr) *Net {
+	n := Net{Name: readName(s), Joined: []PortRef{}}
+	for _, pr := range childrenNamed(child(s, "joined"), "portRef") {
+		ref := PortRef{
+			Port:     ident(pr),
+			Member:   -1,
+			Instance: ident(child(pr, "instanceRef")),
+		}
+		if member := child(pr, "member"); member != nil {
+			ref.Port = ident(member)
+			ref.Member, _ = strconv.Atoi(atom(member, 1))
+		}
+		n.Joined = append(n.Joined, ref)
+	}
+	return &n
+}
+
+func readProperties(s *sexpr.Sexpr) []Property {
+	props := []Property{}
+	for _, p := range childrenNamed(s, "property") {
+		prop := Property{Name: readName(p)}
+		for _, v := range children(p) {
+			if isKeyword(v, "rename") || isKeyword(v, "owner") || isKeyword(v, "unit") {
+				continue
+			}
+			prop.Type = strings.ToLower(v.Name())
+			prop.Value = propertyValue(v)
+			break
+		}
+		props = append(props, prop)
+	}
+	return props
+}
+
+func propertyValue(v *sexpr.Sexpr) string {
+	if e := child(v, "e"); e != nil {
+		return strings.Join(atoms(e), " e ")
+	}
+	if len(children(v)) > 0 {
+		// (boolean (true))
+		return strings.ToLower(children(v)[0].Name())
+	}
+	return strings.Join(atoms(v), " ")
+}
+
+func (i *Instance) Property(name string) (string, bool) {
+	return property(i.Properties, name)
+}
+
+func (v *View) Property(name string) (string, bool) {
+	return property(v.Properties, name)
+}
+
+// property looks a property up by identifier or original name.
+func property(props []Property, name string) (string, bool) {
+	for _, p := range props {
+		if p.Name.Ident == name || p.Name.Original == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,55 @@
+package sexpr
+
+import "strings"
+
+// Unescape returns the value of a quoted string read with escapes on, with
+// each backslash escape replaced by the rune it stands for.
+func Unescape(v string) string {
+	if !strings.Contains(v, `\`) {
+		return v
+	}
+	var sb strings.Builder
+	escaped := false
+	for _, r := range v {
+		if escaped {
+			switch r {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			default:
+				sb.WriteRune(r)
+			}
+			escaped = false
+		} else if r == '\\' {
+			escaped = true
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Escape returns v with backslashes, double quotes and control whitespace
+// escaped, for writing inside a quoted string read back with escapes on.
+func Escape(v string) string {
+	var sb strings.Builder
+	for _, r := range v {
+		switch r {
+		case '"', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
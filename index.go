@@ -0,0 +1,142 @@
+package sexpr
+
+import "strings"
+
+// IndexKeyFunc extracts a lookup key from a sexpr. Sexprs for which it
+// returns false are not indexed under that key.
+type IndexKeyFunc func(sexpr *Sexpr) (string, bool)
+
+// Index provides constant time lookups over the descendants of a root sexpr.
+// It is built lazily on first use and rebuilt automatically when the tree
+// has been modified through this package's setters since the last build.
+type Index struct {
+	root     *Sexpr
+	version  uint64
+	built    bool
+	names    map[string][]*Sexpr
+	paths    map[*Sexpr][]int
+	uuids    map[string]*Sexpr
+	keyFuncs map[string]IndexKeyFunc
+	keys     map[string]map[string][]*Sexpr
+}
+
+func NewIndex(root *Sexpr) *Index {
+	return &Index{
+		root:     root,
+		keyFuncs: map[string]IndexKeyFunc{},
+	}
+}
+
+func (ix *Index) Root() *Sexpr {
+	return ix.root
+}
+
+func (ix *Index) AddKey(key string, fn IndexKeyFunc) {
+	ix.keyFuncs[key] = fn
+	ix.Invalidate()
+}
+
+func (ix *Index) RemoveKey(key string) {
+	delete(ix.keyFuncs, key)
+	ix.Invalidate()
+}
+
+func (ix *Index) Invalidate() {
+	ix.built = false
+}
+
+func (ix *Index) Stale() bool {
+	return !ix.built || ix.version != ix.root.Version()
+}
+
+func (ix *Index) Rebuild() {
+	ix.names = map[string][]*Sexpr{}
+	ix.paths = map[*Sexpr][]int{}
+	ix.uuids = map[string]*Sexpr{}
+	ix.keys = map[string]map[string][]*Sexpr{}
+	for key := range ix.keyFuncs {
+		ix.keys[key] = map[string][]*Sexpr{}
+	}
+
+	ix.paths[ix.root] = []int{}
+	ix.add(ix.root, false)
+	ix.walk(ix.root, []int{})
+
+	ix.version = ix.root.Version()
+	ix.built = true
+}
+
+func (ix *Index) walk(sexpr *Sexpr, path []int) {
+	for i, param := range sexpr.params {
+		child, ok := param.Value().(*Sexpr)
+		if !ok {
+			continue
+		}
+		childPath := make([]int, len(path)+1)
+		copy(childPath, path)
+		childPath[len(path)] = i
+		ix.paths[child] = childPath
+		ix.add(child, true)
+		ix.walk(child, childPath)
+	}
+}
+
+func (ix *Index) add(sexpr *Sexpr, named bool) {
+	if named {
+		name := strings.ToLower(sexpr.Name())
+		ix.names[name] = append(ix.names[name], sexpr)
+	}
+	for _, param := range sexpr.params {
+		child, ok := param.Value().(*Sexpr)
+		if !ok || len(child.params) == 0 {
+			continue
+		}
+		if child.Name() == "uuid" || child.Name() == "tstamp" {
+			if v, err := child.params[0].AsString(); err == nil {
+				ix.uuids[v] = sexpr
+			}
+		}
+	}
+	for key, fn := range ix.keyFuncs {
+		if v, ok := fn(sexpr); ok {
+			ix.keys[key][v] = append(ix.keys[key][v], sexpr)
+		}
+	}
+}
+
+func (ix *Index) ensure() {
+	if ix.Stale() {
+		ix.Rebuild()
+	}
+}
+
+// ByName returns all descendants of the root with the given name, compared
+// case-insensitively as FindChildrenByName does, in depth-first order.
+func (ix *Index) ByName(name string) []*Sexpr {
+	ix.ensure()
+	return ix.names[strings.ToLower(name)]
+}
+
+// ByUUID returns the sexpr carrying a (uuid ...) or (tstamp ...) child with
+// the given value.
+func (ix *Index) ByUUID(uuid string) *Sexpr {
+	ix.ensure()
+	return ix.uuids[uuid]
+}
+
+func (ix *Index) ByKey(key string, value string) []*Sexpr {
+	ix.ensure()
+	return ix.keys[key][value]
+}
+
+// Path returns the param indexes leading from the root to sexpr.
+func (ix *Index) Path(sexpr *Sexpr) ([]int, bool) {
+	ix.ensure()
+	path, ok := ix.paths[sexpr]
+	return path, ok
+}
+
+func (ix *Index) Len() int {
+	ix.ensure()
+	return len(ix.paths)
+}
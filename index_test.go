@@ -0,0 +1,95 @@
+package sexpr
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const indexTestInput = `(kicad_pcb
+	(net 1 GND)
+	(footprint R1 (uuid "aaa") (pad 1 (net 1 GND)) (pad 2))
+	(footprint C1 (tstamp bbb) (pad 1))
+)`
+
+func TestIndexLookups(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(indexTestInput)))
+	require.NoError(t, err)
+
+	ix := NewIndex(root)
+	require.Equal(t, 2, len(ix.ByName("footprint")))
+	require.Equal(t, 2, len(ix.ByName("NET")))
+	require.Equal(t, 3, len(ix.ByName("pad")))
+	require.Nil(t, ix.ByName("kicad_pcb"))
+
+	fp := ix.ByUUID("aaa")
+	require.NotNil(t, fp)
+	require.Equal(t, "R1", fp.Params()[0].String())
+	require.Equal(t, "C1", ix.ByUUID("bbb").Params()[0].String())
+	require.Nil(t, ix.ByUUID("ccc"))
+
+	path, ok := ix.Path(fp)
+	require.True(t, ok)
+	require.Equal(t, []int{1}, path)
+	path, ok = ix.Path(ix.ByName("pad")[1])
+	require.True(t, ok)
+	require.Equal(t, []int{1, 3}, path)
+	path, ok = ix.Path(root)
+	require.True(t, ok)
+	require.Equal(t, []int{}, path)
+	_, ok = ix.Path(NewSexpr("x"))
+	require.False(t, ok)
+}
+
+func TestIndexKeys(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(indexTestInput)))
+	require.NoError(t, err)
+
+	ix := NewIndex(root)
+	ix.AddKey("ref", func(s *Sexpr) (string, bool) {
+		if s.Name() != "footprint" || len(s.Params()) == 0 {
+			return "", false
+		}
+		v, err := s.Params()[0].AsString()
+		return v, err == nil
+	})
+
+	require.Equal(t, 1, len(ix.ByKey("ref", "R1")))
+	require.Equal(t, 1, len(ix.ByKey("ref", "C1")))
+	require.Nil(t, ix.ByKey("ref", "U1"))
+	require.Nil(t, ix.ByKey("other", "R1"))
+
+	ix.RemoveKey("ref")
+	require.Nil(t, ix.ByKey("ref", "R1"))
+}
+
+func TestIndexInvalidation(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(indexTestInput)))
+	require.NoError(t, err)
+
+	ix := NewIndex(root)
+	require.Equal(t, 3, len(ix.ByName("pad")))
+	require.False(t, ix.Stale())
+
+	// mutation of a nested sexpr is detected through the root
+	fp := ix.ByUUID("bbb")
+	pad := NewSexpr("pad")
+	param, err := NewSexprParam(pad)
+	require.NoError(t, err)
+	require.NoError(t, fp.AddParam(len(fp.Params()), param))
+	require.True(t, ix.Stale())
+	require.Equal(t, 4, len(ix.ByName("pad")))
+
+	// changing a uuid value is detected
+	uuid := ix.ByUUID("aaa").FindDirectChildByName("uuid")
+	uuid.Params()[0].Value().(*SexprString).SetValue("zzz")
+	require.Nil(t, ix.ByUUID("aaa"))
+	require.NotNil(t, ix.ByUUID("zzz"))
+
+	// explicit invalidation
+	ix.Invalidate()
+	require.True(t, ix.Stale())
+	require.Equal(t, 4, len(ix.ByName("pad")))
+}
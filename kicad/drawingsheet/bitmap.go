@@ -0,0 +1,89 @@
+package drawingsheet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// bytesPerDataLine matches the line length KiCad writes in pngdata.
+const bytesPerDataLine = 32
+
+// Bitmap is a (bitmap ...) item. PNG holds the image decoded from the
+// pngdata hex dump; it may be replaced or edited in place.
+type Bitmap struct {
+	Item
+	Pos   Coord
+	Scale float64
+	PNG   []byte
+
+	// png is a copy of the image last read or written, so edits to PNG in
+	// place are seen by sync
+	png []byte
+}
+
+func newBitmap(s *sexpr.Sexpr) (*Bitmap, error) {
+	data, err := decodePNGData(node.Child(s, "pngdata"))
+	if err != nil {
+		return nil, err
+	}
+	return &Bitmap{
+		Item:  readItem(s),
+		Pos:   readCoord(node.Child(s, "pos")),
+		Scale: node.ChildFloat(s, "scale", 0),
+		PNG:   data,
+		png:   bytes.Clone(data),
+	}, nil
+}
+
+// decodePNGData reads the (data "89 50 4E ...") lines of a pngdata list.
+func decodePNGData(s *sexpr.Sexpr) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, data := range node.Children(s, "data") {
+		for _, atom := range node.AtomStrings(data) {
+			for _, field := range strings.Fields(atom) {
+				b, err := hex.DecodeString(field)
+				if err != nil || len(b) != 1 {
+					line, col := data.Location()
+					return nil, fmt.Errorf("invalid pngdata byte '%s' at Line %d, Column %d", field, line, col)
+				}
+				buf.WriteByte(b[0])
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func encodePNGData(data []byte) *sexpr.Sexpr {
+	s := node.New("pngdata")
+	for i := 0; i < len(data); i += bytesPerDataLine {
+		end := i + bytesPerDataLine
+		if end > len(data) {
+			end = len(data)
+		}
+		fields := make([]string, 0, end-i)
+		for _, b := range data[i:end] {
+			fields = append(fields, strings.ToUpper(hex.EncodeToString([]byte{b})))
+		}
+		node.Append(s, node.New("data", node.Str(strings.Join(fields, " ")+" ")))
+	}
+	return s
+}
+
+func (b *Bitmap) sync() *sexpr.Sexpr {
+	s := b.syncItem("bitmap")
+	setCoord(s, "pos", b.Pos)
+	node.SetChildIf(s, b.Scale != 0, "scale", node.Float(b.Scale))
+	if !bytes.Equal(b.PNG, b.png) || node.Child(s, "pngdata") == nil {
+		node.RemoveChildren(s, "pngdata")
+		if len(b.PNG) > 0 {
+			node.Append(s, encodePNGData(b.PNG))
+		}
+		b.png = bytes.Clone(b.PNG)
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+// Package drawingsheet maps KiCad drawing sheet templates (.kicad_wks) onto
+// typed structures. Both the current kicad_wks root and the older
+// page_layout root are accepted.
+package drawingsheet
+
+import (
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type DrawingSheet struct {
+	Version   int
+	Generator string
+	Setup     Setup
+	Lines     []*Line
+	Rects     []*Rect
+	Texts     []*Text
+	Bitmaps   []*Bitmap
+
+	root *sexpr.Sexpr
+}
+
+// Setup holds the sheet defaults. Sizes and margins are in millimetres.
+type Setup struct {
+	TextSize      kicad.Point
+	LineWidth     float64
+	TextLineWidth float64
+	LeftMargin    float64
+	RightMargin   float64
+	TopMargin     float64
+	BottomMargin  float64
+}
+
+var roots = []string{"kicad_wks", "page_layout"}
+
+func NewDrawingSheet() *DrawingSheet {
+	return &DrawingSheet{
+		Lines:   []*Line{},
+		Rects:   []*Rect{},
+		Texts:   []*Text{},
+		Bitmaps: []*Bitmap{},
+		root:    node.New("kicad_wks"),
+	}
+}
+
+func Load(r io.Reader) (*DrawingSheet, error) {
+	root, err := node.Read(r, roots...)
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*DrawingSheet, error) {
+	root, err := node.ReadFile(path, roots...)
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*DrawingSheet, error) {
+	if err := node.CheckRoot(root, roots...); err != nil {
+		return nil, err
+	}
+	setup := node.Child(root, "setup")
+	ds := DrawingSheet{
+		Version:   node.ChildInt(root, "version", 0),
+		Generator: node.ChildAtom(root, "generator", 0),
+		Setup: Setup{
+			TextSize:      node.ChildPoint(setup, "textsize"),
+			LineWidth:     node.ChildFloat(setup, "linewidth", 0),
+			TextLineWidth: node.ChildFloat(setup, "textlinewidth", 0),
+			LeftMargin:    node.ChildFloat(setup, "left_margin", 0),
+			RightMargin:   node.ChildFloat(setup, "right_margin", 0),
+			TopMargin:     node.ChildFloat(setup, "top_margin", 0),
+			BottomMargin:  node.ChildFloat(setup, "bottom_margin", 0),
+		},
+		Lines:   []*Line{},
+		Rects:   []*Rect{},
+		Texts:   []*Text{},
+		Bitmaps: []*Bitmap{},
+		root:    root,
+	}
+	for _, child := range node.ChildSexprs(root) {
+		switch child.Name() {
+		case "line":
+			ds.Lines = append(ds.Lines, &Line{Item: readItem(child), Start: readCoord(node.Child(child, "start")), End: readCoord(node.Child(child, "end")), Width: node.ChildFloat(child, "linewidth", 0)})
+		case "rect":
+			ds.Rects = append(ds.Rects, &Rect{Item: readItem(child), Start: readCoord(node.Child(child, "start")), End: readCoord(node.Child(child, "end")), Width: node.ChildFloat(child, "linewidth", 0)})
+		case "tbtext":
+			ds.Texts = append(ds.Texts, newText(child))
+		case "bitmap":
+			b, err := newBitmap(child)
+			if err != nil {
+				return nil, err
+			}
+			ds.Bitmaps = append(ds.Bitmaps, b)
+		}
+	}
+	return &ds, nil
+}
+
+// Sexpr writes the typed fields back into the underlying tree and returns
+// its root.
+func (ds *DrawingSheet) Sexpr() *sexpr.Sexpr {
+	root := ds.root
+	node.SetChildIf(root, ds.Version != 0, "version", node.Int(ds.Version))
+	node.SetChildIf(root, ds.Generator != "", "generator", node.Sym(ds.Generator))
+	ds.syncSetup()
+
+	nodes := []*sexpr.Sexpr{}
+	for _, l := range ds.Lines {
+		nodes = append(nodes, l.sync())
+	}
+	node.Sync(root, []string{"line"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, r := range ds.Rects {
+		nodes = append(nodes, r.sync())
+	}
+	node.Sync(root, []string{"rect"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, t := range ds.Texts {
+		nodes = append(nodes, t.sync())
+	}
+	node.Sync(root, []string{"tbtext"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, b := range ds.Bitmaps {
+		nodes = append(nodes, b.sync())
+	}
+	node.Sync(root, []string{"bitmap"}, nodes)
+
+	return root
+}
+
+func (ds *DrawingSheet) syncSetup() {
+	setup := node.Child(ds.root, "setup")
+	if setup == nil {
+		if ds.Setup == (Setup{}) {
+			return
+		}
+		setup = node.New("setup")
+		node.Append(ds.root, setup)
+	}
+	su := ds.Setup
+	node.SetChildIf(setup, su.TextSize != (kicad.Point{}), "textsize", node.Float(su.TextSize.X), node.Float(su.TextSize.Y))
+	for _, v := range []struct {
+		name string
+		f    float64
+	}{
+		{"linewidth", su.LineWidth},
+		{"textlinewidth", su.TextLineWidth},
+		{"left_margin", su.LeftMargin},
+		{"right_margin", su.RightMargin},
+		{"top_margin", su.TopMargin},
+		{"bottom_margin", su.BottomMargin},
+	} {
+		node.SetChildIf(setup, v.f != 0 || node.Child(setup, v.name) != nil, v.name, node.Float(v.f))
+	}
+}
+
+func (ds *DrawingSheet) Save(w io.Writer) error {
+	return node.Write(w, ds.Sexpr())
+}
+
+func (ds *DrawingSheet) SaveFile(path string) error {
+	return node.WriteFile(path, ds.Sexpr())
+}
+
+// Text returns the first text item whose content is text.
+func (ds *DrawingSheet) Text(text string) *Text {
+	for _, t := range ds.Texts {
+		if t.Text == text {
+			return t
+		}
+	}
+	return nil
+}
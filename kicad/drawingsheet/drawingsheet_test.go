@@ -0,0 +1,150 @@
+package drawingsheet
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	ds, err := LoadFile("testdata/title_block.kicad_wks")
+	require.NoError(t, err)
+
+	require.Equal(t, 20220228, ds.Version)
+	require.Equal(t, "pl_editor", ds.Generator)
+	require.Equal(t, Setup{
+		TextSize:      kicad.Point{X: 1.5, Y: 1.5},
+		LineWidth:     0.15,
+		TextLineWidth: 0.15,
+		LeftMargin:    10,
+		RightMargin:   10,
+		TopMargin:     10,
+		BottomMargin:  10,
+	}, ds.Setup)
+
+	require.Equal(t, 2, len(ds.Rects))
+	require.Equal(t, Coord{X: 110, Y: 34, Corner: CornerRightBottom}, ds.Rects[0].Start)
+	require.Equal(t, "rect around the title block", ds.Rects[0].Comment)
+	require.Equal(t, CornerLeftTop, ds.Rects[1].Start.Corner)
+	require.Equal(t, 2, ds.Rects[1].Repeat)
+
+	require.Equal(t, 1, len(ds.Lines))
+	require.Equal(t, 30, ds.Lines[0].Repeat)
+	require.Equal(t, 50.0, ds.Lines[0].IncrX)
+
+	require.Equal(t, 4, len(ds.Texts))
+	title := ds.Text("Title: ${TITLE}")
+	require.NotNil(t, title)
+	require.True(t, title.Bold)
+	require.True(t, title.Italic)
+	require.Equal(t, kicad.Point{X: 2, Y: 2}, title.FontSize)
+	require.Equal(t, []string{"left"}, title.Justify)
+	require.Equal(t, 100.0, title.MaxLen)
+	require.Equal(t, "page1only", ds.Text("Sheet: ${SHEETPATH}").Option)
+
+	require.Equal(t, 1, len(ds.Bitmaps))
+	logo := ds.Bitmaps[0]
+	require.Equal(t, "logo", logo.Name)
+	require.Equal(t, 0.5, logo.Scale)
+	require.Equal(t, 67, len(logo.PNG))
+	img, err := png.Decode(bytes.NewReader(logo.PNG))
+	require.NoError(t, err)
+	require.Equal(t, 1, img.Bounds().Dx())
+}
+
+func TestLoadBadPNGData(t *testing.T) {
+	_, err := Load(strings.NewReader(`(kicad_wks (bitmap (pngdata (data "89 5G"))))`))
+	require.ErrorContains(t, err, "invalid pngdata byte '5G'")
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/title_block.kicad_wks")
+	require.NoError(t, err)
+	root, err := node.Read(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	ds, err := Load(bytes.NewReader(data))
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, ds.Save(&sb))
+	require.Equal(t, root.String()+"\n", sb.String())
+}
+
+func TestSaveModified(t *testing.T) {
+	ds, err := LoadFile("testdata/title_block.kicad_wks")
+	require.NoError(t, err)
+
+	ds.Setup.LeftMargin = 5
+	ds.Rects = ds.Rects[:1]
+	ds.Lines[0].End.Corner = CornerRightBottom
+	title := ds.Text("Title: ${TITLE}")
+	title.Italic = false
+	title.Text = "Project: ${TITLE}"
+	ds.Texts = append(ds.Texts, &Text{Text: "Rev: ${REVISION}", Pos: Coord{X: 24, Y: 5.3}, FontSize: kicad.Point{X: 1.5, Y: 1.5}})
+	png := append([]byte{}, ds.Bitmaps[0].PNG...)
+	ds.Bitmaps[0].PNG = png[:40]
+	ds.Bitmaps = append(ds.Bitmaps, &Bitmap{Pos: Coord{X: 1, Y: 2, Corner: CornerLeftTop}, Scale: 1, PNG: png})
+
+	var sb strings.Builder
+	require.NoError(t, ds.Save(&sb))
+	out := sb.String()
+
+	ds, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Equal(t, 5.0, ds.Setup.LeftMargin)
+	require.Equal(t, 1, len(ds.Rects))
+	require.Equal(t, CornerRightBottom, ds.Lines[0].End.Corner)
+	title = ds.Text("Project: ${TITLE}")
+	require.NotNil(t, title)
+	require.True(t, title.Bold)
+	require.False(t, title.Italic)
+	rev := ds.Text("Rev: ${REVISION}")
+	require.NotNil(t, rev)
+	require.Equal(t, kicad.Point{X: 1.5, Y: 1.5}, rev.FontSize)
+	require.Equal(t, png[:40], ds.Bitmaps[0].PNG)
+	require.Equal(t, png, ds.Bitmaps[1].PNG)
+	require.Equal(t, CornerLeftTop, ds.Bitmaps[1].Pos.Corner)
+
+	require.Contains(t, out, `(name "")`)
+	require.Contains(t, out, `(data "42 60 82 ")`)
+}
+
+func TestSaveBitmapEditedInPlace(t *testing.T) {
+	ds, err := LoadFile("testdata/title_block.kicad_wks")
+	require.NoError(t, err)
+	logo := ds.Bitmaps[0]
+	logo.PNG[len(logo.PNG)-1] = 0xFF
+
+	var sb strings.Builder
+	require.NoError(t, ds.Save(&sb))
+	saved, err := Load(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	require.Equal(t, logo.PNG, saved.Bitmaps[0].PNG)
+
+	// an edit after a save is written by the next one
+	logo.PNG[0] = 0x00
+	sb.Reset()
+	require.NoError(t, ds.Save(&sb))
+	saved, err = Load(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	require.Equal(t, byte(0x00), saved.Bitmaps[0].PNG[0])
+	require.Equal(t, logo.PNG, saved.Bitmaps[0].PNG)
+}
+
+func TestPageLayout(t *testing.T) {
+	ds, err := Load(strings.NewReader(`(page_layout
+  (setup (textsize 1.5 1.5) (linewidth 0.15) (textlinewidth 0.15))
+  (line (name segm1:Line) (start 50 2 ltcorner) (end 50 0 ltcorner) (repeat 30) (incrx 50)))`))
+	require.NoError(t, err)
+	require.Equal(t, "segm1:Line", ds.Lines[0].Name)
+	require.Equal(t, "page_layout", ds.Sexpr().Name())
+
+	_, err = Load(strings.NewReader(`(kicad_pcb)`))
+	require.ErrorContains(t, err, "unexpected root")
+}
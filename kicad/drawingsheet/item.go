@@ -0,0 +1,121 @@
+package drawingsheet
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Corner names the page corner a coordinate is measured from. KiCad omits
+// it for the default, CornerRightBottom.
+type Corner string
+
+const (
+	CornerRightBottom Corner = "rbcorner"
+	CornerRightTop    Corner = "rtcorner"
+	CornerLeftBottom  Corner = "lbcorner"
+	CornerLeftTop     Corner = "ltcorner"
+)
+
+type Coord struct {
+	X      float64
+	Y      float64
+	Corner Corner
+}
+
+// Item holds the fields common to all drawing sheet items. Option is
+// "page1only", "notonpage1" or empty.
+type Item struct {
+	Name    string
+	Comment string
+	Option  string
+	Repeat  int
+	IncrX   float64
+	IncrY   float64
+
+	node *sexpr.Sexpr
+}
+
+type Line struct {
+	Item
+	Start Coord
+	End   Coord
+	Width float64
+}
+
+type Rect struct {
+	Item
+	Start Coord
+	End   Coord
+	Width float64
+}
+
+func readItem(s *sexpr.Sexpr) Item {
+	return Item{
+		Name:    node.ChildAtom(s, "name", 0),
+		Comment: node.ChildAtom(s, "comment", 0),
+		Option:  node.ChildAtom(s, "option", 0),
+		Repeat:  node.ChildInt(s, "repeat", 0),
+		IncrX:   node.ChildFloat(s, "incrx", 0),
+		IncrY:   node.ChildFloat(s, "incry", 0),
+		node:    s,
+	}
+}
+
+func readCoord(s *sexpr.Sexpr) Coord {
+	c := Coord{X: node.AtomFloat(s, 0), Y: node.AtomFloat(s, 1), Corner: CornerRightBottom}
+	if corner := node.Atom(s, 2); corner != "" {
+		c.Corner = Corner(corner)
+	}
+	return c
+}
+
+func (it *Item) Node() *sexpr.Sexpr {
+	return it.node
+}
+
+func (it *Item) syncItem(name string) *sexpr.Sexpr {
+	if it.node == nil {
+		it.node = node.New(name, node.New("name", node.Str(it.Name)))
+	}
+	s := it.node
+	if child := node.Child(s, "name"); child != nil || it.Name != "" {
+		node.SetChild(s, "name", node.Str(it.Name))
+	}
+	node.SetChildIf(s, it.Option != "", "option", node.Sym(it.Option))
+	node.SetChildIf(s, it.Repeat != 0, "repeat", node.Int(it.Repeat))
+	node.SetChildIf(s, it.IncrX != 0, "incrx", node.Float(it.IncrX))
+	node.SetChildIf(s, it.IncrY != 0, "incry", node.Float(it.IncrY))
+	node.SetChildIf(s, it.Comment != "", "comment", node.Str(it.Comment))
+	return s
+}
+
+func setCoord(s *sexpr.Sexpr, name string, c Coord) {
+	atoms := []*sexpr.SexprString{node.Float(c.X), node.Float(c.Y)}
+	if c.Corner != "" && c.Corner != CornerRightBottom || node.Atom(node.Child(s, name), 2) != "" {
+		atoms = append(atoms, node.Sym(string(c.corner())))
+	}
+	node.SetChild(s, name, atoms...)
+}
+
+func (c Coord) corner() Corner {
+	if c.Corner == "" {
+		return CornerRightBottom
+	}
+	return c.Corner
+}
+
+func (l *Line) sync() *sexpr.Sexpr {
+	s := l.syncItem("line")
+	setCoord(s, "start", l.Start)
+	setCoord(s, "end", l.End)
+	node.SetChildIf(s, l.Width != 0, "linewidth", node.Float(l.Width))
+	return s
+}
+
+func (r *Rect) sync() *sexpr.Sexpr {
+	s := r.syncItem("rect")
+	setCoord(s, "start", r.Start)
+	setCoord(s, "end", r.End)
+	node.SetChildIf(s, r.Width != 0, "linewidth", node.Float(r.Width))
+	return s
+}
@@ -0,0 +1,68 @@
+package drawingsheet
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Text is a (tbtext ...) item. Text may contain ${VAR} references that
+// KiCad expands when drawing the sheet.
+type Text struct {
+	Item
+	Text      string
+	Pos       Coord
+	Rotate    float64
+	FontSize  kicad.Point
+	Bold      bool
+	Italic    bool
+	Justify   []string
+	MaxLen    float64
+	MaxHeight float64
+	IncrLabel int
+}
+
+func newText(s *sexpr.Sexpr) *Text {
+	font := node.Child(s, "font")
+	return &Text{
+		Item:      readItem(s),
+		Text:      node.Atom(s, 0),
+		Pos:       readCoord(node.Child(s, "pos")),
+		Rotate:    node.ChildFloat(s, "rotate", 0),
+		FontSize:  node.ChildPoint(font, "size"),
+		Bold:      node.Flag(font, "bold"),
+		Italic:    node.Flag(font, "italic"),
+		Justify:   node.AtomStrings(node.Child(s, "justify")),
+		MaxLen:    node.ChildFloat(s, "maxlen", 0),
+		MaxHeight: node.ChildFloat(s, "maxheight", 0),
+		IncrLabel: node.ChildInt(s, "incrlabel", 0),
+	}
+}
+
+func (t *Text) sync() *sexpr.Sexpr {
+	s := t.syncItem("tbtext")
+	node.SetAtom(s, 0, node.Str(t.Text))
+	setCoord(s, "pos", t.Pos)
+	node.SetChildIf(s, t.Rotate != 0, "rotate", node.Float(t.Rotate))
+
+	font := node.Child(s, "font")
+	if font == nil && (t.FontSize != (kicad.Point{}) || t.Bold || t.Italic) {
+		font = node.New("font")
+		node.Append(s, font)
+	}
+	if font != nil {
+		node.SetChildIf(font, t.FontSize != (kicad.Point{}), "size", node.Float(t.FontSize.X), node.Float(t.FontSize.Y))
+		node.SetFlag(font, "bold", t.Bold)
+		node.SetFlag(font, "italic", t.Italic)
+	}
+
+	justify := []*sexpr.SexprString{}
+	for _, j := range t.Justify {
+		justify = append(justify, node.Sym(j))
+	}
+	node.SetChildIf(s, len(justify) > 0, "justify", justify...)
+	node.SetChildIf(s, t.MaxLen != 0, "maxlen", node.Float(t.MaxLen))
+	node.SetChildIf(s, t.MaxHeight != 0, "maxheight", node.Float(t.MaxHeight))
+	node.SetChildIf(s, t.IncrLabel != 0, "incrlabel", node.Int(t.IncrLabel))
+	return s
+}
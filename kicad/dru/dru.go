@@ -0,0 +1,247 @@
+// Package dru reads and writes KiCad custom design rules (.kicad_dru).
+//
+// A rules file is a sequence of top-level forms: a (version ...) header
+// followed by (rule ...) forms. Lines starting with '#' are comments; each
+// is kept with the form that follows it and written back before that form,
+// so a comment inside a form moves to just before the next one.
+package dru
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type DesignRules struct {
+	Version int
+	Rules   []*Rule
+
+	// forms holds the top-level forms as children so unknown ones keep
+	// their place among the rules.
+	forms *sexpr.Sexpr
+	// comments holds the comments before forms other than rules, which
+	// keep theirs in Rule.Comments, and trailing those after the last form.
+	comments map[*sexpr.Sexpr][]string
+	trailing []string
+}
+
+func NewDesignRules() *DesignRules {
+	return &DesignRules{Version: 1, Rules: []*Rule{}, forms: node.New("kicad_dru"), comments: map[*sexpr.Sexpr][]string{}}
+}
+
+func Load(r io.Reader) (*DesignRules, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, comments := stripComments(data)
+	lexer := sexpr.NewLexer(bufio.NewReader(bytes.NewReader(data)))
+	lexer.SetEscapes(true)
+	roots, err := sexpr.ParseAllLexer(lexer)
+	if err != nil {
+		return nil, err
+	}
+	dr, err := FromSexprs(roots)
+	if err != nil {
+		return nil, err
+	}
+	dr.attach(comments)
+	return dr, nil
+}
+
+func LoadFile(path string) (*DesignRules, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	dr, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dr, nil
+}
+
+func FromSexprs(roots []*sexpr.Sexpr) (*DesignRules, error) {
+	if len(roots) == 0 {
+		return nil, errors.New("empty document")
+	}
+	if roots[0].Name() != "version" {
+		line, col := roots[0].Location()
+		return nil, fmt.Errorf("unexpected '%s' at Line %d, Column %d, expected 'version'", roots[0].Name(), line, col)
+	}
+	dr := DesignRules{
+		Version:  node.AtomInt(roots[0], 0),
+		Rules:    []*Rule{},
+		forms:    node.New("kicad_dru"),
+		comments: map[*sexpr.Sexpr][]string{},
+	}
+	for _, root := range roots {
+		node.Append(dr.forms, root)
+		if root.Name() == "rule" {
+			r, err := newRule(root)
+			if err != nil {
+				return nil, err
+			}
+			dr.Rules = append(dr.Rules, r)
+		}
+	}
+	return &dr, nil
+}
+
+// Sexprs writes the typed fields back into the underlying forms and returns
+// them in file order.
+func (dr *DesignRules) Sexprs() []*sexpr.Sexpr {
+	version := node.Child(dr.forms, "version")
+	if version == nil {
+		version = node.New("version")
+		node.Insert(dr.forms, 0, version)
+	}
+	node.SetAtoms(version, node.Int(dr.Version))
+
+	nodes := []*sexpr.Sexpr{}
+	for _, r := range dr.Rules {
+		nodes = append(nodes, r.sync())
+	}
+	node.Sync(dr.forms, []string{"rule"}, nodes)
+
+	return node.ChildSexprs(dr.forms)
+}
+
+func (dr *DesignRules) Save(w io.Writer) error {
+	forms := dr.Sexprs()
+	rules := map[*sexpr.Sexpr]*Rule{}
+	for _, r := range dr.Rules {
+		rules[r.node] = r
+	}
+	for _, form := range forms {
+		comments := dr.comments[form]
+		if r, ok := rules[form]; ok {
+			comments = r.Comments
+		}
+		if err := writeComments(w, comments); err != nil {
+			return err
+		}
+		if err := node.Write(w, form); err != nil {
+			return err
+		}
+	}
+	return writeComments(w, dr.trailing)
+}
+
+func writeComments(w io.Writer, comments []string) error {
+	for _, c := range comments {
+		if _, err := io.WriteString(w, c+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dr *DesignRules) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := dr.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (dr *DesignRules) Rule(name string) *Rule {
+	for _, r := range dr.Rules {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// attach gives each form the comments on the lines before it that no
+// earlier form took; those after the last form are kept as trailing.
+func (dr *DesignRules) attach(comments []comment) {
+	rules := map[*sexpr.Sexpr]*Rule{}
+	for _, r := range dr.Rules {
+		rules[r.node] = r
+	}
+	for _, form := range node.ChildSexprs(dr.forms) {
+		line, _ := form.Location()
+		texts := []string{}
+		for len(comments) > 0 && comments[0].line < line {
+			texts = append(texts, comments[0].text)
+			comments = comments[1:]
+		}
+		if len(texts) == 0 {
+			continue
+		}
+		if r, ok := rules[form]; ok {
+			r.Comments = texts
+		} else {
+			dr.comments[form] = texts
+		}
+	}
+	for _, c := range comments {
+		dr.trailing = append(dr.trailing, c.text)
+	}
+}
+
+type comment struct {
+	line int
+	text string
+}
+
+// stripComments blanks out lines whose first non-blank character is '#',
+// keeping line and column numbers intact for error messages, and returns
+// the blanked comments. A '#' line inside a quoted string is left alone.
+func stripComments(data []byte) ([]byte, []comment) {
+	out := bytes.Clone(data)
+	comments := []comment{}
+	line := 1
+	start := true
+	quoted := false
+	escaped := false
+	from := -1
+	for i, b := range out {
+		if b == '\n' {
+			if from != -1 {
+				comments = append(comments, comment{line: line, text: strings.TrimRight(string(data[from:i]), "\r")})
+				from = -1
+			}
+			line += 1
+			start = !quoted
+			continue
+		}
+		switch {
+		case from != -1:
+			out[i] = ' '
+		case quoted:
+			if escaped {
+				escaped = false
+			} else if b == '\\' {
+				escaped = true
+			} else if b == '"' {
+				quoted = false
+			}
+		case start && b == '#':
+			from = i
+			out[i] = ' '
+		case start && (b == ' ' || b == '\t' || b == '\r'):
+		default:
+			start = false
+			quoted = b == '"'
+		}
+	}
+	if from != -1 {
+		comments = append(comments, comment{line: line, text: strings.TrimRight(string(data[from:]), "\r")})
+	}
+	return out, comments
+}
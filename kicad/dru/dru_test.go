@@ -0,0 +1,170 @@
+package dru
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	dr, err := LoadFile("testdata/board.kicad_dru")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, dr.Version)
+	require.Equal(t, 5, len(dr.Rules))
+
+	edge := dr.Rule("Board edge")
+	require.NotNil(t, edge)
+	require.Equal(t, "outer", edge.Layer)
+	require.Equal(t, &Value{Number: 0.5, Unit: "mm"}, edge.Constraint("edge_clearance").Min)
+
+	hv := dr.Rule("HV clearance")
+	require.Equal(t, "error", hv.Severity)
+	require.Equal(t, "A.NetClass == 'HV' && B.NetClass != 'HV'", hv.Condition)
+
+	bga := dr.Rule("No vias under BGA")
+	require.Equal(t, []string{"via", "micro_via"}, bga.Constraint("disallow").Args)
+
+	dp := dr.Rule("DP skew")
+	skew := dp.Constraint("skew")
+	require.Nil(t, skew.Min)
+	require.Equal(t, &Value{Number: 10, Unit: "mil"}, skew.Max)
+	mm, ok := skew.Max.MM()
+	require.True(t, ok)
+	require.InDelta(t, 0.254, mm, 1e-9)
+	tw := dp.Constraint("track_width")
+	require.Equal(t, 0.2, tw.Opt.Number)
+
+	require.Equal(t, []string{"A.Type == 'Via'"}, dr.Rule("Assert").Constraint("assertion").Args)
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load(strings.NewReader(""))
+	require.ErrorContains(t, err, "empty document")
+
+	_, err = Load(strings.NewReader(`(rule x)`))
+	require.ErrorContains(t, err, "expected 'version'")
+
+	_, err = Load(strings.NewReader("(version 1)\n(rule x (constraint clearance (min 2furlong)))"))
+	require.ErrorContains(t, err, "invalid value '2furlong' at Line 2")
+}
+
+func TestParseValue(t *testing.T) {
+	for in, want := range map[string]Value{
+		"0.2mm": {0.2, "mm"},
+		"6mil":  {6, "mil"},
+		"1in":   {1, "in"},
+		"45deg": {45, "deg"},
+		"0.1":   {0.1, ""},
+		"-3um":  {-3, "um"},
+	} {
+		v, err := ParseValue(in)
+		require.NoError(t, err)
+		require.Equal(t, want, v)
+		require.Equal(t, in, v.String())
+	}
+	_, ok := Value{45, "deg"}.MM()
+	require.False(t, ok)
+	_, err := ParseValue("mm")
+	require.Error(t, err)
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/board.kicad_dru")
+	require.NoError(t, err)
+	stripped, comments := stripComments(data)
+	require.Equal(t, []comment{{2, "# Keep copper away from the board edge"}, {10, "# trailing comment inside the file"}}, comments)
+	lexer := sexpr.NewLexer(bufio.NewReader(bytes.NewReader(stripped)))
+	lexer.SetEscapes(true)
+	roots, err := sexpr.ParseAllLexer(lexer)
+	require.NoError(t, err)
+	want := ""
+	for i, root := range roots {
+		switch i {
+		case 1:
+			want += comments[0].text + "\n"
+		case 3:
+			want += comments[1].text + "\n"
+		}
+		want += root.String() + "\n"
+	}
+
+	dr, err := Load(bytes.NewReader(data))
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, dr.Save(&sb))
+	require.Equal(t, want, sb.String())
+}
+
+func TestSaveModified(t *testing.T) {
+	dr, err := LoadFile("testdata/board.kicad_dru")
+	require.NoError(t, err)
+
+	dr.Rule("HV clearance").Constraint("clearance").Min = &Value{Number: 3, Unit: "mm"}
+	dr.Rule("DP skew").Constraint("skew").Max = nil
+	dr.Rule("No vias under BGA").Constraint("disallow").Args = []string{"via"}
+	dr.Rules = append(dr.Rules[1:], &Rule{
+		Name:      "Thermal",
+		Condition: "A.Type == 'Pad'",
+		Constraints: []*Constraint{
+			{Kind: "thermal_spoke_width", Min: &Value{Number: 0.3, Unit: "mm"}},
+		},
+	})
+
+	var sb strings.Builder
+	require.NoError(t, dr.Save(&sb))
+	out := sb.String()
+
+	dr, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Nil(t, dr.Rule("Board edge"))
+	require.Equal(t, "3mm", dr.Rule("HV clearance").Constraint("clearance").Min.String())
+	require.Nil(t, dr.Rule("DP skew").Constraint("skew").Max)
+	require.Equal(t, []string{"via"}, dr.Rule("No vias under BGA").Constraint("disallow").Args)
+	th := dr.Rule("Thermal")
+	require.NotNil(t, th)
+	require.Equal(t, "A.Type == 'Pad'", th.Condition)
+	require.Equal(t, 0.3, th.Constraints[0].Min.Number)
+
+	require.True(t, strings.HasPrefix(out, "(version 1)\n"))
+	require.Contains(t, out, "(within_diff_pairs)")
+	require.Contains(t, out, `(constraint assertion "A.Type == 'Via'")`)
+}
+
+func TestSaveComments(t *testing.T) {
+	in := "# header\n(version 1)\n# first\n#  second\n(rule a\n\t(condition \"A.x\n# not a comment\n\\\"#\\\"\"))\n(rule b)\n# last"
+	dr, err := Load(strings.NewReader(in))
+	require.NoError(t, err)
+	require.Equal(t, []string{"# first", "#  second"}, dr.Rule("a").Comments)
+	require.Equal(t, "A.x\n# not a comment\n\"#\"", dr.Rule("a").Condition)
+	require.Nil(t, dr.Rule("b").Comments)
+
+	var sb strings.Builder
+	require.NoError(t, dr.Save(&sb))
+	want := "# header\n(version 1)\n# first\n#  second\n(rule a\n\t(condition \"A.x\n# not a comment\n\\\"#\\\"\")\n)\n(rule b)\n# last\n"
+	require.Equal(t, want, sb.String())
+
+	// comments go with their rule when it is removed
+	dr.Rules = []*Rule{dr.Rule("b")}
+	dr.Rule("b").Comments = []string{"# now first"}
+	sb.Reset()
+	require.NoError(t, dr.Save(&sb))
+	require.Equal(t, "# header\n(version 1)\n# now first\n(rule b)\n# last\n", sb.String())
+}
+
+func TestNewDesignRules(t *testing.T) {
+	dr := NewDesignRules()
+	dr.Rules = append(dr.Rules, &Rule{
+		Name:        "Min track",
+		Layer:       "inner",
+		Constraints: []*Constraint{{Kind: "track_width", Min: &Value{Number: 0.1, Unit: "mm"}}},
+	})
+	var sb strings.Builder
+	require.NoError(t, dr.Save(&sb))
+	require.Equal(t, "(version 1)\n(rule \"Min track\"\n\t(layer inner)\n\t(constraint track_width\n\t\t(min 0.1mm)\n\t)\n)\n", sb.String())
+}
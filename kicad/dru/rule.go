@@ -0,0 +1,154 @@
+package dru
+
+import (
+	"fmt"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Rule is a (rule NAME ...) form. Layer and Severity are KiCad keywords
+// such as "outer" or "warning"; Condition is the expression string as
+// written in the file. Comments holds the '#' lines written before the
+// rule, '#' included.
+type Rule struct {
+	Name        string
+	Layer       string
+	Condition   string
+	Severity    string
+	Constraints []*Constraint
+	Comments    []string
+
+	node *sexpr.Sexpr
+}
+
+// Constraint is a (constraint KIND ...) entry. Args holds the atoms after
+// the kind, e.g. the items of (constraint disallow via micro_via) or the
+// expression of an assertion. Min, Opt and Max are nil when absent.
+type Constraint struct {
+	Kind string
+	Args []string
+	Min  *Value
+	Opt  *Value
+	Max  *Value
+
+	node *sexpr.Sexpr
+}
+
+func newRule(s *sexpr.Sexpr) (*Rule, error) {
+	r := Rule{
+		Name:        node.Atom(s, 0),
+		Layer:       node.ChildAtom(s, "layer", 0),
+		Condition:   node.ChildAtom(s, "condition", 0),
+		Severity:    node.ChildAtom(s, "severity", 0),
+		Constraints: []*Constraint{},
+		node:        s,
+	}
+	for _, cs := range node.Children(s, "constraint") {
+		c, err := newConstraint(cs)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s': %w", r.Name, err)
+		}
+		r.Constraints = append(r.Constraints, c)
+	}
+	return &r, nil
+}
+
+func newConstraint(s *sexpr.Sexpr) (*Constraint, error) {
+	atoms := node.AtomStrings(s)
+	if len(atoms) == 0 {
+		line, col := s.Location()
+		return nil, fmt.Errorf("constraint without kind at Line %d, Column %d", line, col)
+	}
+	c := Constraint{
+		Kind: atoms[0],
+		Args: atoms[1:],
+		node: s,
+	}
+	for _, limit := range []struct {
+		name string
+		v    **Value
+	}{{"min", &c.Min}, {"opt", &c.Opt}, {"max", &c.Max}} {
+		child := node.Child(s, limit.name)
+		if child == nil {
+			continue
+		}
+		v, err := ParseValue(node.Atom(child, 0))
+		if err != nil {
+			line, col := child.Location()
+			return nil, fmt.Errorf("%s at Line %d, Column %d", err.Error(), line, col)
+		}
+		*limit.v = &v
+	}
+	return &c, nil
+}
+
+func (r *Rule) Constraint(kind string) *Constraint {
+	for _, c := range r.Constraints {
+		if c.Kind == kind {
+			return c
+		}
+	}
+	return nil
+}
+
+func (r *Rule) sync() *sexpr.Sexpr {
+	if r.node == nil {
+		r.node = node.New("rule")
+	}
+	s := r.node
+	node.SetAtoms(s, node.Str(r.Name))
+	node.SetChildIf(s, r.Layer != "", "layer", node.Sym(r.Layer))
+	node.SetChildIf(s, r.Severity != "", "severity", node.Sym(r.Severity))
+	node.SetChildIf(s, r.Condition != "", "condition", node.Str(r.Condition))
+
+	nodes := []*sexpr.Sexpr{}
+	for _, c := range r.Constraints {
+		nodes = append(nodes, c.sync())
+	}
+	node.Sync(s, []string{"constraint"}, nodes)
+	return s
+}
+
+func (c *Constraint) sync() *sexpr.Sexpr {
+	if c.node == nil {
+		c.node = node.New("constraint")
+	}
+	s := c.node
+	atoms := []*sexpr.SexprString{node.Sym(c.Kind)}
+	for i, arg := range c.Args {
+		// keep assertion expressions and other quoted args quoted
+		if old := node.Atoms(s); i+1 < len(old) && old[i+1].Quoted() || arg == "" || needsQuote(arg) {
+			atoms = append(atoms, node.Str(arg))
+		} else {
+			atoms = append(atoms, node.Sym(arg))
+		}
+	}
+	node.SetAtoms(s, atoms...)
+	setLimit(s, "min", c.Min)
+	setLimit(s, "opt", c.Opt)
+	setLimit(s, "max", c.Max)
+	return s
+}
+
+func setLimit(s *sexpr.Sexpr, name string, v *Value) {
+	if v == nil {
+		node.RemoveChildren(s, name)
+		return
+	}
+	if child := node.Child(s, name); child != nil {
+		if old, err := ParseValue(node.Atom(child, 0)); err == nil && old == *v {
+			return
+		}
+	}
+	node.SetChild(s, name, node.Sym(v.String()))
+}
+
+func needsQuote(v string) bool {
+	for _, r := range v {
+		if r == ' ' || r == '\t' || r == '\n' || r == '(' || r == ')' || r == '"' || r == '\'' {
+			return true
+		}
+	}
+	return false
+}
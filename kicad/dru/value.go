@@ -0,0 +1,58 @@
+package dru
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Value is a number with an optional unit suffix as used in rule limits,
+// e.g. 0.2mm, 6mil or 45deg.
+type Value struct {
+	Number float64
+	Unit   string
+}
+
+var units = []string{"mm", "um", "mils", "mil", "in", "deg"}
+
+var mmPerUnit = map[string]float64{
+	"mm":   1,
+	"um":   0.001,
+	"mil":  0.0254,
+	"mils": 0.0254,
+	"in":   25.4,
+}
+
+func ParseValue(v string) (Value, error) {
+	s := strings.TrimSpace(v)
+	unit := ""
+	for _, u := range units {
+		if strings.HasSuffix(s, u) {
+			unit = u
+			s = strings.TrimSpace(s[:len(s)-len(u)])
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return Value{}, fmt.Errorf("invalid value '%s'", v)
+	}
+	return Value{Number: f, Unit: unit}, nil
+}
+
+func (v Value) String() string {
+	return strconv.FormatFloat(v.Number, 'f', -1, 64) + v.Unit
+}
+
+// MM converts a length to millimetres. Values without a unit are taken to
+// be millimetres, as KiCad does; angles and unknown units report false.
+func (v Value) MM() (float64, bool) {
+	if v.Unit == "" {
+		return v.Number, true
+	}
+	f, ok := mmPerUnit[v.Unit]
+	if !ok {
+		return 0, false
+	}
+	return v.Number * f, true
+}
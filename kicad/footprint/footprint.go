@@ -0,0 +1,217 @@
+// Package footprint maps KiCad footprint files (.kicad_mod) onto typed
+// structures, and loads footprint libraries (.pretty directories).
+//
+// Both the current (footprint ...) root and the KiCad 5 (module ...) root
+// are read. Lines, text, pads and 3D models are modelled; arcs, circles and
+// polygons are kept only as sexprs.
+package footprint
+
+import (
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Footprint is a library footprint. New Reference and Value fields are
+// written as properties on the silkscreen and fabrication layers.
+type Footprint struct {
+	Name        string
+	Version     int
+	Generator   string
+	Layer       string
+	Description string
+	Tags        string
+	Attributes  []string
+	Reference   string
+	Value       string
+	Properties  []*Property
+	Pads        []*Pad
+	Lines       []*FpLine
+	Texts       []*FpText
+	Models      []*Model
+
+	root   *sexpr.Sexpr
+	fields node.Fields
+}
+
+type Property struct {
+	Name  string
+	Value string
+
+	node *sexpr.Sexpr
+}
+
+func NewFootprint(name string) *Footprint {
+	return &Footprint{
+		Name:  name,
+		Layer: "F.Cu",
+		root:  node.New("footprint"),
+	}
+}
+
+func Load(r io.Reader) (*Footprint, error) {
+	root, err := node.Read(r, "footprint", "module")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Footprint, error) {
+	root, err := node.ReadFile(path, "footprint", "module")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Footprint, error) {
+	if err := node.CheckRoot(root, "footprint", "module"); err != nil {
+		return nil, err
+	}
+	fp := Footprint{
+		Name:        node.Atom(root, 0),
+		Version:     node.ChildInt(root, "version", 0),
+		Generator:   node.ChildAtom(root, "generator", 0),
+		Layer:       node.ChildAtom(root, "layer", 0),
+		Description: node.ChildAtom(root, "descr", 0),
+		Tags:        node.ChildAtom(root, "tags", 0),
+		Attributes:  node.AtomStrings(node.Child(root, "attr")),
+		Properties:  []*Property{},
+		Pads:        []*Pad{},
+		Lines:       []*FpLine{},
+		Texts:       []*FpText{},
+		Models:      []*Model{},
+		root:        root,
+	}
+	for _, child := range node.ChildSexprs(root) {
+		if fp.fields.Read(child, &fp.Reference, &fp.Value) {
+			continue
+		}
+		switch child.Name() {
+		case "property":
+			fp.Properties = append(fp.Properties, &Property{Name: node.Atom(child, 0), Value: node.Atom(child, 1), node: child})
+		case "fp_text":
+			fp.Texts = append(fp.Texts, newFpText(child))
+		case "fp_line":
+			fp.Lines = append(fp.Lines, newFpLine(child))
+		case "pad":
+			fp.Pads = append(fp.Pads, newPad(child))
+		case "model":
+			fp.Models = append(fp.Models, newModel(child))
+		}
+	}
+	return &fp, nil
+}
+
+// Sexpr writes the typed fields back into the underlying tree and returns
+// its root.
+func (fp *Footprint) Sexpr() *sexpr.Sexpr {
+	s := fp.root
+	node.SetAtom(s, 0, node.Str(fp.Name))
+	node.SetChildIf(s, fp.Version != 0, "version", node.Int(fp.Version))
+	node.SetChildIf(s, fp.Generator != "", "generator", node.Str(fp.Generator))
+	node.SetChild(s, "layer", node.Str(fp.Layer))
+	node.SetChildIf(s, fp.Description != "", "descr", node.Str(fp.Description))
+	node.SetChildIf(s, fp.Tags != "", "tags", node.Str(fp.Tags))
+
+	properties, texts := fp.fields.Sync(fp.Reference, fp.Value, func(name string) *sexpr.Sexpr {
+		if name == "Reference" {
+			return newFieldProperty(name, "F.SilkS")
+		}
+		return newFieldProperty(name, "F.Fab")
+	})
+	for _, p := range fp.Properties {
+		if p.node == nil {
+			p.node = newFieldProperty(p.Name, "F.Fab")
+			node.SetFlag(node.Child(p.node, "effects"), "hide", true)
+		}
+		node.SetAtom(p.node, 0, node.Str(p.Name))
+		node.SetAtom(p.node, 1, node.Str(p.Value))
+		properties = append(properties, p.node)
+	}
+	node.Sync(s, []string{"property"}, properties)
+
+	attrs := []*sexpr.SexprString{}
+	for _, a := range fp.Attributes {
+		attrs = append(attrs, node.Sym(a))
+	}
+	node.SetChildIf(s, len(attrs) > 0, "attr", attrs...)
+
+	for _, t := range fp.Texts {
+		texts = append(texts, t.sync())
+	}
+	node.Sync(s, []string{"fp_text"}, texts)
+
+	nodes := []*sexpr.Sexpr{}
+	for _, l := range fp.Lines {
+		nodes = append(nodes, l.sync())
+	}
+	node.Sync(s, []string{"fp_line"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, p := range fp.Pads {
+		nodes = append(nodes, p.sync())
+	}
+	node.Sync(s, []string{"pad"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, m := range fp.Models {
+		nodes = append(nodes, m.sync())
+	}
+	node.Sync(s, []string{"model"}, nodes)
+
+	return s
+}
+
+func (fp *Footprint) Save(w io.Writer) error {
+	return node.Write(w, fp.Sexpr())
+}
+
+func (fp *Footprint) SaveFile(path string) error {
+	return node.WriteFile(path, fp.Sexpr())
+}
+
+func (fp *Footprint) Property(name string) (string, bool) {
+	for _, p := range fp.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+func (fp *Footprint) SetProperty(name string, value string) {
+	for _, p := range fp.Properties {
+		if p.Name == name {
+			p.Value = value
+			return
+		}
+	}
+	fp.Properties = append(fp.Properties, &Property{Name: name, Value: value})
+}
+
+func (fp *Footprint) Pad(number string) *Pad {
+	for _, p := range fp.Pads {
+		if p.Number == number {
+			return p
+		}
+	}
+	return nil
+}
+
+func newFieldProperty(name string, layer string) *sexpr.Sexpr {
+	return node.New("property", node.Str(name), node.Str(""),
+		node.New("at", node.Int(0), node.Int(0), node.Int(0)),
+		node.New("layer", node.Str(layer)),
+		node.New("effects", node.New("font",
+			node.New("size", node.Int(1), node.Int(1)),
+			node.New("thickness", node.Float(0.15)),
+		)),
+	)
+}
+
+func (p *Property) Node() *sexpr.Sexpr {
+	return p.node
+}
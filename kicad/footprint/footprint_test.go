@@ -0,0 +1,148 @@
+package footprint
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fp, err := LoadFile("testdata/Test.pretty/R_0603.kicad_mod")
+	require.NoError(t, err)
+
+	require.Equal(t, "R_0603", fp.Name)
+	require.Equal(t, 20240108, fp.Version)
+	require.Equal(t, "Resistor SMD 0603", fp.Description)
+	require.Equal(t, []string{"smd"}, fp.Attributes)
+	require.Equal(t, "REF**", fp.Reference)
+	require.Equal(t, "R_0603", fp.Value)
+	require.Equal(t, 1, len(fp.Lines))
+	require.Equal(t, 0.12, fp.Lines[0].Width)
+	require.Equal(t, 1, len(fp.Texts))
+	require.Equal(t, "${REFERENCE}", fp.Texts[0].Text)
+	require.Equal(t, 2, len(fp.Pads))
+	require.Equal(t, kicad.Position{X: 0.825}, fp.Pad("2").At)
+	require.Equal(t, 1, len(fp.Models))
+	require.Equal(t, XYZ{X: 1, Y: 1, Z: 1}, fp.Models[0].Scale)
+}
+
+func TestLoadLegacy(t *testing.T) {
+	fp, err := LoadFile("testdata/Test.pretty/Pin_Legacy.kicad_mod")
+	require.NoError(t, err)
+
+	require.Equal(t, "REF**", fp.Reference)
+	require.Equal(t, "Pin_Legacy", fp.Value)
+	require.Equal(t, 1, len(fp.Texts))
+	require.True(t, fp.Texts[0].Hidden)
+	require.Equal(t, 0.12, fp.Lines[0].Width)
+	require.Equal(t, kicad.Drill{Size: kicad.Point{X: 1, Y: 1}}, fp.Pads[0].Drill)
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	for _, name := range []string{"R_0603", "Pin_Legacy"} {
+		data, err := os.ReadFile("testdata/Test.pretty/" + name + ".kicad_mod")
+		require.NoError(t, err)
+		root, err := node.Read(strings.NewReader(string(data)))
+		require.NoError(t, err)
+
+		fp, err := Load(strings.NewReader(string(data)))
+		require.NoError(t, err)
+		var sb strings.Builder
+		require.NoError(t, fp.Save(&sb))
+		require.Equal(t, root.String()+"\n", sb.String())
+	}
+}
+
+func TestSaveModified(t *testing.T) {
+	fp, err := LoadFile("testdata/Test.pretty/Pin_Legacy.kicad_mod")
+	require.NoError(t, err)
+
+	fp.Name = "Pin_1"
+	fp.Value = "Pin_1"
+	fp.Texts[0].Hidden = false
+	fp.Lines[0].Width = 0.15
+	fp.Pads = append(fp.Pads, &Pad{Number: "2", Type: "smd", Shape: "rect", Size: kicad.Point{X: 1, Y: 1}, Layers: []string{"F.Cu"}})
+	fp.Models = append(fp.Models, &Model{Path: "pin.step"})
+	fp.SetProperty("Sim.Enable", "0")
+
+	var sb strings.Builder
+	require.NoError(t, fp.Save(&sb))
+	out := sb.String()
+
+	fp, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Equal(t, "Pin_1", fp.Name)
+	require.Equal(t, "Pin_1", fp.Value)
+	require.False(t, fp.Texts[0].Hidden)
+	require.Equal(t, 0.15, fp.Lines[0].Width)
+	require.Equal(t, 2, len(fp.Pads))
+	require.Equal(t, XYZ{X: 1, Y: 1, Z: 1}, fp.Models[0].Scale)
+	v, _ := fp.Property("Sim.Enable")
+	require.Equal(t, "0", v)
+	require.Contains(t, out, "(tedit 5A1DBFB0)")
+	require.Contains(t, out, `(fp_text value "Pin_1"`)
+}
+
+func TestNewFootprint(t *testing.T) {
+	fp := NewFootprint("TP")
+	fp.Reference = "TP**"
+	fp.Value = "TP"
+	fp.Attributes = []string{"smd"}
+	fp.Lines = append(fp.Lines, &FpLine{End: kicad.Point{X: 1}, Layer: "F.SilkS", Width: 0.1})
+
+	var sb strings.Builder
+	require.NoError(t, fp.Save(&sb))
+
+	fp, err := Load(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	require.Equal(t, "TP", fp.Name)
+	require.Equal(t, "TP**", fp.Reference)
+	require.Equal(t, "F.Cu", fp.Layer)
+	require.Equal(t, 0.1, fp.Lines[0].Width)
+}
+
+func TestPretty(t *testing.T) {
+	p, err := OpenPretty("testdata/Test.pretty")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Broken", "Pin_Legacy", "R_0603"}, p.Names())
+	require.Equal(t, 0, len(p.cache))
+
+	fp, err := p.Footprint("R_0603")
+	require.NoError(t, err)
+	require.Equal(t, "R_0603", fp.Name)
+	require.Equal(t, 1, len(p.cache))
+	again, _ := p.Footprint("R_0603")
+	require.Same(t, fp, again)
+
+	_, err = p.Footprint("Missing")
+	require.Error(t, err)
+
+	loaded := []string{}
+	failed := []string{}
+	p.Each(func(name string, each *Footprint, err error) bool {
+		if err != nil {
+			failed = append(failed, name)
+		} else {
+			loaded = append(loaded, each.Name)
+		}
+		if name == "R_0603" {
+			require.Same(t, fp, each)
+		}
+		return true
+	})
+	require.Equal(t, []string{"Broken"}, failed)
+	require.Equal(t, []string{"Pin_Legacy", "R_0603"}, loaded)
+	// Each does not fill the cache
+	require.Equal(t, 1, len(p.cache))
+
+	count := 0
+	p.Each(func(name string, fp *Footprint, err error) bool {
+		count += 1
+		return false
+	})
+	require.Equal(t, 1, count)
+}
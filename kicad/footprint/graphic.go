@@ -0,0 +1,113 @@
+package footprint
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type FpLine struct {
+	Start kicad.Point
+	End   kicad.Point
+	Layer string
+	Width float64
+	UUID  string
+
+	node *sexpr.Sexpr
+}
+
+// FpText is a user text item; reference and value texts are exposed as
+// Footprint.Reference and Footprint.Value instead.
+type FpText struct {
+	Text   string
+	At     kicad.Position
+	Layer  string
+	Hidden bool
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+func newFpLine(s *sexpr.Sexpr) *FpLine {
+	l := FpLine{
+		Start: node.ChildPoint(s, "start"),
+		End:   node.ChildPoint(s, "end"),
+		Layer: node.ChildAtom(s, "layer", 0),
+		Width: node.ChildFloat(s, "width", 0),
+		UUID:  node.UUID(s),
+		node:  s,
+	}
+	if stroke := node.Child(s, "stroke"); stroke != nil {
+		l.Width = node.ChildFloat(stroke, "width", 0)
+	}
+	return &l
+}
+
+func (l *FpLine) Node() *sexpr.Sexpr {
+	return l.node
+}
+
+func (l *FpLine) sync() *sexpr.Sexpr {
+	if l.node == nil {
+		l.node = node.New("fp_line")
+	}
+	s := l.node
+	node.SetChildPoint(s, "start", l.Start)
+	node.SetChildPoint(s, "end", l.End)
+	if node.Child(s, "width") != nil {
+		node.SetChild(s, "width", node.Float(l.Width))
+	} else {
+		stroke := node.Child(s, "stroke")
+		if stroke == nil {
+			stroke = node.New("stroke", node.New("width", node.Int(0)), node.New("type", node.Sym("solid")))
+			node.Append(s, stroke)
+		}
+		node.SetChild(stroke, "width", node.Float(l.Width))
+	}
+	node.SetChild(s, "layer", node.Str(l.Layer))
+	node.SetUUID(s, l.UUID)
+	return s
+}
+
+func newFpText(s *sexpr.Sexpr) *FpText {
+	hidden := node.Flag(s, "hide")
+	if effects := node.Child(s, "effects"); effects != nil && node.Flag(effects, "hide") {
+		hidden = true
+	}
+	return &FpText{
+		Text:   node.Atom(s, 1),
+		At:     node.ChildPosition(s, "at"),
+		Layer:  node.ChildAtom(s, "layer", 0),
+		Hidden: hidden,
+		UUID:   node.UUID(s),
+		node:   s,
+	}
+}
+
+func (t *FpText) Node() *sexpr.Sexpr {
+	return t.node
+}
+
+func (t *FpText) sync() *sexpr.Sexpr {
+	if t.node == nil {
+		t.node = node.New("fp_text", node.Sym("user"))
+		node.SetAtom(t.node, 1, node.Str(t.Text))
+		node.SetChildPosition(t.node, "at", t.At)
+		node.Append(t.node, node.New("layer", node.Str(t.Layer)))
+		node.Append(t.node, node.New("effects", node.New("font",
+			node.New("size", node.Int(1), node.Int(1)),
+			node.New("thickness", node.Float(0.15)),
+		)))
+	}
+	s := t.node
+	node.SetAtom(s, 1, node.Str(t.Text))
+	node.SetChildPosition(s, "at", t.At)
+	node.SetChild(s, "layer", node.Str(t.Layer))
+	if effects := node.Child(s, "effects"); effects != nil && node.Child(effects, "hide") != nil {
+		node.SetFlag(effects, "hide", t.Hidden)
+	} else {
+		node.SetFlag(s, "hide", t.Hidden)
+	}
+	node.SetUUID(s, t.UUID)
+	return s
+}
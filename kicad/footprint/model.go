@@ -0,0 +1,68 @@
+package footprint
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type XYZ struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// Model is a 3D model reference. Offset is in millimetres and Rotate in
+// degrees.
+type Model struct {
+	Path   string
+	Offset XYZ
+	Scale  XYZ
+	Rotate XYZ
+	Hidden bool
+
+	node *sexpr.Sexpr
+}
+
+func newModel(s *sexpr.Sexpr) *Model {
+	return &Model{
+		Path:   node.Atom(s, 0),
+		Offset: readXYZ(node.Child(s, "offset")),
+		Scale:  readXYZ(node.Child(s, "scale")),
+		Rotate: readXYZ(node.Child(s, "rotate")),
+		Hidden: node.Flag(s, "hide"),
+		node:   s,
+	}
+}
+
+func readXYZ(s *sexpr.Sexpr) XYZ {
+	xyz := node.Child(s, "xyz")
+	return XYZ{X: node.AtomFloat(xyz, 0), Y: node.AtomFloat(xyz, 1), Z: node.AtomFloat(xyz, 2)}
+}
+
+func (m *Model) Node() *sexpr.Sexpr {
+	return m.node
+}
+
+func (m *Model) sync() *sexpr.Sexpr {
+	if m.node == nil {
+		m.node = node.New("model")
+		if m.Scale == (XYZ{}) {
+			m.Scale = XYZ{X: 1, Y: 1, Z: 1}
+		}
+	}
+	s := m.node
+	node.SetAtom(s, 0, node.Str(m.Path))
+	node.SetFlag(s, "hide", m.Hidden)
+	for _, v := range []struct {
+		name string
+		xyz  XYZ
+	}{{"offset", m.Offset}, {"scale", m.Scale}, {"rotate", m.Rotate}} {
+		child := node.Child(s, v.name)
+		if child == nil {
+			child = node.New(v.name)
+			node.Append(s, child)
+		}
+		node.SetChild(child, "xyz", node.Float(v.xyz.X), node.Float(v.xyz.Y), node.Float(v.xyz.Z))
+	}
+	return s
+}
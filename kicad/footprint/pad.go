@@ -0,0 +1,53 @@
+package footprint
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Pad struct {
+	Number string
+	Type   string
+	Shape  string
+	At     kicad.Position
+	Size   kicad.Point
+	Drill  kicad.Drill
+	Layers []string
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+func newPad(s *sexpr.Sexpr) *Pad {
+	shared := node.ReadPad(s)
+	return &Pad{
+		Number: shared.Number,
+		Type:   shared.Type,
+		Shape:  shared.Shape,
+		At:     shared.At,
+		Size:   shared.Size,
+		Drill:  shared.Drill,
+		Layers: shared.Layers,
+		UUID:   node.UUID(s),
+		node:   s,
+	}
+}
+
+func (p *Pad) Node() *sexpr.Sexpr {
+	return p.node
+}
+
+func (p *Pad) sync() *sexpr.Sexpr {
+	p.node = node.SyncPad(p.node, node.Pad{
+		Number: p.Number,
+		Type:   p.Type,
+		Shape:  p.Shape,
+		At:     p.At,
+		Size:   p.Size,
+		Drill:  p.Drill,
+		Layers: p.Layers,
+	})
+	node.SetUUID(p.node, p.UUID)
+	return p.node
+}
@@ -0,0 +1,99 @@
+package footprint
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+const Extension = ".kicad_mod"
+
+// Pretty is a footprint library directory. Footprints are listed when the
+// library is opened but only parsed when first requested.
+type Pretty struct {
+	fsys  fs.FS
+	names []string
+	cache map[string]*Footprint
+}
+
+func OpenPretty(dir string) (*Pretty, error) {
+	return OpenPrettyFS(os.DirFS(dir))
+}
+
+func OpenPrettyFS(fsys fs.FS) (*Pretty, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), Extension) {
+			names = append(names, strings.TrimSuffix(e.Name(), Extension))
+		}
+	}
+	sort.Strings(names)
+	return &Pretty{fsys: fsys, names: names, cache: map[string]*Footprint{}}, nil
+}
+
+func (p *Pretty) Names() []string {
+	return p.names
+}
+
+func (p *Pretty) Len() int {
+	return len(p.names)
+}
+
+// Footprint returns the named footprint, parsing it on the first request
+// and caching it until Forget is called for it.
+func (p *Pretty) Footprint(name string) (*Footprint, error) {
+	if fp, ok := p.cache[name]; ok {
+		return fp, nil
+	}
+	fp, err := p.load(name)
+	if err != nil {
+		return nil, err
+	}
+	p.cache[name] = fp
+	return fp, nil
+}
+
+func (p *Pretty) load(name string) (*Footprint, error) {
+	f, err := p.fsys.Open(path.Clean(name + Extension))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := node.Read(f, "footprint", "module")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name+Extension, err)
+	}
+	return FromSexpr(root)
+}
+
+// Each loads the footprints in name order, calling fn for each until it
+// returns false. Parse errors are passed to fn rather than stopping the walk.
+// Footprints already cached are passed as they are; the rest are parsed
+// without being cached, so walking a large library holds one footprint at a
+// time.
+func (p *Pretty) Each(fn func(name string, fp *Footprint, err error) bool) {
+	for _, name := range p.names {
+		fp, ok := p.cache[name]
+		var err error
+		if !ok {
+			fp, err = p.load(name)
+		}
+		if !fn(name, fp, err) {
+			return
+		}
+	}
+}
+
+// Forget drops a parsed footprint from the cache.
+func (p *Pretty) Forget(name string) {
+	delete(p.cache, name)
+}
@@ -0,0 +1,118 @@
+package format
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+var (
+	pcbKinds = []FileKind{FileBoard, FileFootprint}
+	schKinds = []FileKind{FileSchematic, FileSymbolLib}
+)
+
+// Default returns a registry holding the syntax changes between KiCad 5
+// and 8 that the document models in this module rely on.
+func Default() *Registry {
+	r := NewRegistry()
+	r.Register(Migration{Name: "module-to-footprint", Kinds: pcbKinds, Version: 20211014, Apply: moduleToFootprint})
+	r.Register(Migration{Name: "tstamp-to-uuid", Kinds: pcbKinds, Version: 20240108, Apply: tstampToUUID})
+	for _, v := range []struct {
+		kinds   []FileKind
+		version int
+	}{{schKinds, 20231120}, {pcbKinds, 20240108}} {
+		r.Register(Migration{Name: "flags-to-children", Kinds: v.kinds, Version: v.version, Apply: flagsToChildren})
+		r.Register(Migration{Name: "quote-generator", Kinds: v.kinds, Version: v.version, Apply: quoteGenerator("8.0")})
+	}
+	return r
+}
+
+func all(root *sexpr.Sexpr, name string) []*sexpr.Sexpr {
+	found := root.FindChildren(func(s *sexpr.Sexpr, depth int) bool {
+		return s.Name() == name
+	}, -1)
+	if root.Name() == name {
+		found = append([]*sexpr.Sexpr{root}, found...)
+	}
+	return found
+}
+
+// moduleToFootprint renames the KiCad 5 (module ...) to (footprint ...).
+func moduleToFootprint(root *sexpr.Sexpr) error {
+	if root.Name() == "module" {
+		root.SetName("footprint")
+	}
+	for _, m := range node.Children(root, "module") {
+		m.SetName("footprint")
+	}
+	return nil
+}
+
+// tstampToUUID rewrites (tstamp X) as (uuid "X").
+func tstampToUUID(root *sexpr.Sexpr) error {
+	for _, ts := range all(root, "tstamp") {
+		ts.SetName("uuid")
+		quoteAtoms(ts)
+	}
+	return nil
+}
+
+// flagsToChildren rewrites bare flags such as "hide", and "bold"/"italic"
+// inside fonts, as (flag yes) children in the same position.
+func flagsToChildren(root *sexpr.Sexpr) error {
+	for _, s := range append([]*sexpr.Sexpr{root}, root.FindChildren(func(*sexpr.Sexpr, int) bool { return true }, -1)...) {
+		flags := []string{"hide"}
+		if s.Name() == "font" {
+			flags = append(flags, "bold", "italic")
+		}
+		for i := 0; i < len(s.Params()); i++ {
+			param := s.Params()[i]
+			ss, ok := param.Value().(*sexpr.SexprString)
+			if !ok || ss.Quoted() || !hasFlag(flags, ss.Value()) {
+				continue
+			}
+			param.SetValue(node.New(ss.Value(), node.Sym("yes")))
+		}
+	}
+	return nil
+}
+
+// quoteAtoms quotes the bare string params of s. SetAtoms would keep them
+// bare since their values are unchanged.
+func quoteAtoms(s *sexpr.Sexpr) {
+	for _, param := range s.Params() {
+		if ss, ok := param.Value().(*sexpr.SexprString); ok && !ss.Quoted() {
+			param.SetValue(node.Str(ss.Value()))
+		}
+	}
+}
+
+func hasFlag(flags []string, v string) bool {
+	for _, f := range flags {
+		if f == v {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteGenerator quotes the generator name and adds the generator_version
+// KiCad 8 writes after it.
+func quoteGenerator(version string) func(*sexpr.Sexpr) error {
+	return func(root *sexpr.Sexpr) error {
+		gen := node.Child(root, "generator")
+		if gen == nil {
+			return nil
+		}
+		quoteAtoms(gen)
+		if node.Child(root, "generator_version") != nil {
+			return nil
+		}
+		for i, param := range root.Params() {
+			if param.Value() == gen {
+				node.Insert(root, i+1, node.New("generator_version", node.Str(version)))
+				break
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package format
+
+type FileKind int
+
+const (
+	FileUnknown FileKind = iota
+	FileBoard
+	FileFootprint
+	FileSchematic
+	FileSymbolLib
+	FileDrawingSheet
+	FileLibTable
+)
+
+func (k FileKind) String() string {
+	switch k {
+	case FileBoard:
+		return "board"
+	case FileFootprint:
+		return "footprint"
+	case FileSchematic:
+		return "schematic"
+	case FileSymbolLib:
+		return "symbol library"
+	case FileDrawingSheet:
+		return "drawing sheet"
+	case FileLibTable:
+		return "library table"
+	default:
+		return "unknown"
+	}
+}
+
+func fileKind(root string) FileKind {
+	switch root {
+	case "kicad_pcb":
+		return FileBoard
+	case "footprint", "module":
+		return FileFootprint
+	case "kicad_sch":
+		return FileSchematic
+	case "kicad_symbol_lib":
+		return FileSymbolLib
+	case "kicad_wks", "page_layout":
+		return FileDrawingSheet
+	case "fp_lib_table", "sym_lib_table":
+		return FileLibTable
+	default:
+		return FileUnknown
+	}
+}
@@ -0,0 +1,93 @@
+// Package format detects which KiCad release wrote a file and upgrades
+// parsed trees between file format versions.
+package format
+
+import (
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Info describes the format of a parsed KiCad file. Version is the
+// (version ...) stamp, a YYYYMMDD date for most files, or 0 when absent.
+// Major is the KiCad release the stamp belongs to, or 0 when unknown.
+type Info struct {
+	Kind             FileKind
+	Root             string
+	Version          int
+	Generator        string
+	GeneratorVersion string
+	Major            int
+}
+
+type release struct {
+	version int
+	major   int
+}
+
+// releases lists, per file kind, the format version each KiCad release
+// first wrote. Development versions in between count towards the previous
+// release.
+var releases = map[FileKind][]release{
+	FileBoard:        {{20211014, 6}, {20221018, 7}, {20240108, 8}, {20241229, 9}},
+	FileFootprint:    {{20211014, 6}, {20221018, 7}, {20240108, 8}, {20241229, 9}},
+	FileSchematic:    {{20211123, 6}, {20230121, 7}, {20231120, 8}, {20250114, 9}},
+	FileSymbolLib:    {{20211014, 6}, {20220914, 7}, {20231120, 8}, {20241209, 9}},
+	FileDrawingSheet: {{20210606, 6}, {20220228, 7}, {20231118, 8}},
+}
+
+// Detect reads the version, generator and file kind of a parsed KiCad
+// file.
+func Detect(root *sexpr.Sexpr) Info {
+	info := Info{
+		Kind:             fileKind(root.Name()),
+		Root:             root.Name(),
+		Version:          node.ChildInt(root, "version", 0),
+		Generator:        node.ChildAtom(root, "generator", 0),
+		GeneratorVersion: node.ChildAtom(root, "generator_version", 0),
+	}
+	info.Major = major(info)
+	return info
+}
+
+func major(info Info) int {
+	if info.GeneratorVersion != "" {
+		if m, err := strconv.Atoi(strings.SplitN(info.GeneratorVersion, ".", 2)[0]); err == nil {
+			return m
+		}
+	}
+	if info.Root == "module" || info.Root == "page_layout" {
+		return 5
+	}
+	rs, ok := releases[info.Kind]
+	if !ok {
+		return 0
+	}
+	if info.Version == 0 {
+		// KiCad 5 footprints carry no version
+		if info.Kind == FileFootprint {
+			return 5
+		}
+		return 0
+	}
+	m := 5
+	for _, r := range rs {
+		if info.Version >= r.version {
+			m = r.major
+		}
+	}
+	return m
+}
+
+// Version returns the format version KiCad major release writes for kind,
+// or 0 when unknown.
+func Version(kind FileKind, major int) int {
+	for _, r := range releases[kind] {
+		if r.major == major {
+			return r.version
+		}
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package format
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, input string) *sexpr.Sexpr {
+	root, err := sexpr.Parse(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	return root
+}
+
+func TestDetect(t *testing.T) {
+	for input, want := range map[string]Info{
+		`(kicad_pcb (version 20171130) (host pcbnew 5.1.9))`:                            {Kind: FileBoard, Root: "kicad_pcb", Version: 20171130, Major: 5},
+		`(kicad_pcb (version 20221018) (generator pcbnew))`:                             {Kind: FileBoard, Root: "kicad_pcb", Version: 20221018, Generator: "pcbnew", Major: 7},
+		`(kicad_pcb (version 20240108) (generator "pcbnew") (generator_version "8.0"))`: {Kind: FileBoard, Root: "kicad_pcb", Version: 20240108, Generator: "pcbnew", GeneratorVersion: "8.0", Major: 8},
+		`(kicad_sch (version 20230121) (generator eeschema))`:                           {Kind: FileSchematic, Root: "kicad_sch", Version: 20230121, Generator: "eeschema", Major: 7},
+		`(kicad_symbol_lib (version 20220914) (generator kicad_symbol_editor))`:         {Kind: FileSymbolLib, Root: "kicad_symbol_lib", Version: 20220914, Generator: "kicad_symbol_editor", Major: 7},
+		`(module R_0603 (layer F.Cu) (tedit 5F68FEEE))`:                                 {Kind: FileFootprint, Root: "module", Major: 5},
+		`(footprint "R_0603" (version 20211014) (generator pcbnew) (layer "F.Cu"))`:     {Kind: FileFootprint, Root: "footprint", Version: 20211014, Generator: "pcbnew", Major: 6},
+		`(fp_lib_table (version 7))`:                                                    {Kind: FileLibTable, Root: "fp_lib_table", Version: 7},
+		`(something (version 3))`:                                                       {Kind: FileUnknown, Root: "something", Version: 3},
+	} {
+		require.Equal(t, want, Detect(parse(t, input)), input)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	require.Equal(t, 20240108, Version(FileBoard, 8))
+	require.Equal(t, 20231120, Version(FileSchematic, 8))
+	require.Equal(t, 0, Version(FileBoard, 4))
+	require.Equal(t, 0, Version(FileLibTable, 7))
+}
@@ -0,0 +1,91 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Migration is a syntax change introduced by the format version Version of
+// the file kinds in Kinds. Apply rewrites a tree from the previous syntax
+// to the new one in place.
+type Migration struct {
+	Name    string
+	Kinds   []FileKind
+	Version int
+	Apply   func(root *sexpr.Sexpr) error
+}
+
+type Registry struct {
+	migrations []Migration
+}
+
+func NewRegistry() *Registry {
+	return &Registry{migrations: []Migration{}}
+}
+
+func (r *Registry) Register(m Migration) {
+	r.migrations = append(r.migrations, m)
+}
+
+// Migrations returns the migrations for kind that apply when upgrading from
+// version from to version to, in the order they run.
+func (r *Registry) Migrations(kind FileKind, from int, to int) []Migration {
+	ms := []Migration{}
+	for _, m := range r.migrations {
+		if m.Version > from && m.Version <= to && hasKind(m.Kinds, kind) {
+			ms = append(ms, m)
+		}
+	}
+	sort.SliceStable(ms, func(i, j int) bool {
+		return ms[i].Version < ms[j].Version
+	})
+	return ms
+}
+
+// Migrate upgrades root to format version to by applying every registered
+// migration between its current version and to, then stamps the new
+// version. It returns the names of the migrations applied.
+func (r *Registry) Migrate(root *sexpr.Sexpr, to int) ([]string, error) {
+	info := Detect(root)
+	if info.Kind == FileUnknown {
+		return nil, fmt.Errorf("unknown file kind '%s'", info.Root)
+	}
+	if to < info.Version {
+		return nil, fmt.Errorf("cannot downgrade %s from version %d to %d", info.Kind, info.Version, to)
+	}
+	applied := []string{}
+	for _, m := range r.Migrations(info.Kind, info.Version, to) {
+		if err := m.Apply(root); err != nil {
+			return applied, fmt.Errorf("migration '%s': %w", m.Name, err)
+		}
+		applied = append(applied, m.Name)
+	}
+	setVersion(root, to)
+	return applied, nil
+}
+
+func setVersion(root *sexpr.Sexpr, v int) {
+	if node.Child(root, "version") != nil {
+		node.SetChild(root, "version", node.Int(v))
+		return
+	}
+	// the version goes right after the root's own atoms, e.g. a footprint
+	// name
+	idx := 0
+	for idx < len(root.Params()) && root.Params()[idx].Kind() == sexpr.SexprParamKindString {
+		idx += 1
+	}
+	node.Insert(root, idx, node.New("version", node.Int(v)))
+}
+
+func hasKind(kinds []FileKind, kind FileKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package format
+
+import (
+	"errors"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateFootprint(t *testing.T) {
+	root := parse(t, `(module R_0603 (layer F.Cu) (tedit 5F68FEEE)
+  (fp_text reference REF** (at 0 -1.43) (layer F.SilkS) hide
+    (effects (font (size 1 1) (thickness 0.15) italic)))
+  (pad 1 smd roundrect (at -0.7875 0) (size 0.875 0.95) (layers F.Cu F.Paste F.Mask) (tstamp 0a1b)))`)
+
+	applied, err := Default().Migrate(root, Version(FileFootprint, 8))
+	require.NoError(t, err)
+	require.Equal(t, []string{"module-to-footprint", "tstamp-to-uuid", "flags-to-children", "quote-generator"}, applied)
+
+	info := Detect(root)
+	require.Equal(t, FileFootprint, info.Kind)
+	require.Equal(t, 8, info.Major)
+	require.Equal(t, `(footprint R_0603
+	(version 20240108)
+	(layer F.Cu)
+	(tedit 5F68FEEE)
+	(fp_text reference REF**
+		(at 0 -1.43)
+		(layer F.SilkS)
+		(hide yes)
+		(effects
+			(font
+				(size 1 1)
+				(thickness 0.15)
+				(italic yes)
+			)
+		)
+	)
+	(pad 1 smd roundrect
+		(at -0.7875 0)
+		(size 0.875 0.95)
+		(layers F.Cu F.Paste F.Mask)
+		(uuid "0a1b")
+	)
+)`, root.String())
+}
+
+func TestMigrateSchematic(t *testing.T) {
+	root := parse(t, `(kicad_sch (version 20230121) (generator eeschema) (uuid "x")
+  (lib_symbols (symbol "Device:R" (pin_numbers hide) (property "Reference" "R" (at 0 0 0) (effects (font (size 1.27 1.27)) hide)))))`)
+
+	applied, err := Default().Migrate(root, Version(FileSchematic, 8))
+	require.NoError(t, err)
+	require.Equal(t, []string{"flags-to-children", "quote-generator"}, applied)
+	require.Equal(t, Info{Kind: FileSchematic, Root: "kicad_sch", Version: 20231120, Generator: "eeschema", GeneratorVersion: "8.0", Major: 8}, Detect(root))
+
+	out := root.String()
+	require.Contains(t, out, "(generator \"eeschema\")\n\t(generator_version \"8.0\")")
+	require.Contains(t, out, "(pin_numbers\n\t\t\t\t(hide yes)\n\t\t\t)")
+	require.NotContains(t, out, " hide")
+
+	// already current: nothing to apply
+	applied, err = Default().Migrate(root, Version(FileSchematic, 8))
+	require.NoError(t, err)
+	require.Equal(t, []string{}, applied)
+}
+
+func TestMigrateErrors(t *testing.T) {
+	_, err := Default().Migrate(parse(t, `(kicad_pcb (version 20240108))`), 20211014)
+	require.ErrorContains(t, err, "cannot downgrade board")
+
+	_, err = Default().Migrate(parse(t, `(foo)`), 1)
+	require.ErrorContains(t, err, "unknown file kind 'foo'")
+
+	r := NewRegistry()
+	r.Register(Migration{Name: "broken", Kinds: []FileKind{FileBoard}, Version: 2, Apply: func(*sexpr.Sexpr) error {
+		return errors.New("boom")
+	}})
+	_, err = r.Migrate(parse(t, `(kicad_pcb (version 1))`), 3)
+	require.ErrorContains(t, err, "migration 'broken': boom")
+}
+
+func TestRegistryOrder(t *testing.T) {
+	r := NewRegistry()
+	noop := func(*sexpr.Sexpr) error { return nil }
+	r.Register(Migration{Name: "b", Kinds: []FileKind{FileBoard}, Version: 30, Apply: noop})
+	r.Register(Migration{Name: "a", Kinds: []FileKind{FileBoard}, Version: 20, Apply: noop})
+	r.Register(Migration{Name: "c", Kinds: []FileKind{FileSchematic}, Version: 25, Apply: noop})
+	r.Register(Migration{Name: "d", Kinds: []FileKind{FileBoard}, Version: 40, Apply: noop})
+
+	root := parse(t, `(kicad_pcb (version 10))`)
+	applied, err := r.Migrate(root, 30)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, applied)
+	require.Equal(t, 30, Detect(root).Version)
+}
@@ -0,0 +1,114 @@
+package node
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+)
+
+// Pad holds the fields of a pad that library footprints and board
+// footprints share.
+type Pad struct {
+	Number string
+	Type   string
+	Shape  string
+	At     kicad.Position
+	Size   kicad.Point
+	Drill  kicad.Drill
+	Layers []string
+}
+
+func ReadPad(s *sexpr.Sexpr) Pad {
+	p := Pad{
+		Number: Atom(s, 0),
+		Type:   Atom(s, 1),
+		Shape:  Atom(s, 2),
+		At:     ChildPosition(s, "at"),
+		Size:   ChildPoint(s, "size"),
+		Layers: AtomStrings(Child(s, "layers")),
+	}
+	if drill := Child(s, "drill"); drill != nil {
+		if Atom(drill, 0) == "oval" {
+			p.Drill = kicad.Drill{Oval: true, Size: kicad.Point{X: AtomFloat(drill, 1), Y: AtomFloat(drill, 2)}}
+		} else {
+			d := AtomFloat(drill, 0)
+			p.Drill = kicad.Drill{Size: kicad.Point{X: d, Y: d}}
+		}
+	}
+	return p
+}
+
+// SyncPad writes p into s, creating s when it is nil, and returns it.
+func SyncPad(s *sexpr.Sexpr, p Pad) *sexpr.Sexpr {
+	if s == nil {
+		s = New("pad")
+	}
+	SetAtom(s, 0, Str(p.Number))
+	SetAtom(s, 1, Sym(p.Type))
+	SetAtom(s, 2, Sym(p.Shape))
+	SetChildPosition(s, "at", p.At)
+	SetChildPoint(s, "size", p.Size)
+	switch {
+	case p.Drill.Size == kicad.Point{}:
+		RemoveChildren(s, "drill")
+	case p.Drill.Oval:
+		SetChild(s, "drill", Sym("oval"), Float(p.Drill.Size.X), Float(p.Drill.Size.Y))
+	default:
+		SetChild(s, "drill", Float(p.Drill.Size.X))
+	}
+	layers := []*sexpr.SexprString{}
+	for _, l := range p.Layers {
+		layers = append(layers, Str(l))
+	}
+	SetChild(s, "layers", layers...)
+	return s
+}
+
+// Fields tracks the sexprs holding the Reference and Value of a footprint.
+// They are read from the (property "Reference" ...) form of KiCad 8 or the
+// (fp_text reference ...) form of earlier versions, and written back to
+// whichever was present.
+type Fields struct {
+	reference *sexpr.Sexpr
+	value     *sexpr.Sexpr
+}
+
+// Read stores the text of child in reference or value and reports true
+// when child is one of those fields.
+func (f *Fields) Read(child *sexpr.Sexpr, reference *string, value *string) bool {
+	form := child.Name() + " " + Atom(child, 0)
+	switch form {
+	case "property Reference", "fp_text reference":
+		f.reference = child
+		*reference = Atom(child, 1)
+	case "property Value", "fp_text value":
+		f.value = child
+		*value = Atom(child, 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// Sync writes reference and value back, creating missing fields with
+// newField, and returns the fields in the property form and in the fp_text
+// form for the caller to sync with the rest of their kind.
+func (f *Fields) Sync(reference string, value string, newField func(name string) *sexpr.Sexpr) ([]*sexpr.Sexpr, []*sexpr.Sexpr) {
+	if f.reference == nil {
+		f.reference = newField("Reference")
+	}
+	if f.value == nil {
+		f.value = newField("Value")
+	}
+	SetAtom(f.reference, 1, Str(reference))
+	SetAtom(f.value, 1, Str(value))
+	properties := []*sexpr.Sexpr{}
+	texts := []*sexpr.Sexpr{}
+	for _, field := range []*sexpr.Sexpr{f.reference, f.value} {
+		if field.Name() == "property" {
+			properties = append(properties, field)
+		} else {
+			texts = append(texts, field)
+		}
+	}
+	return properties, texts
+}
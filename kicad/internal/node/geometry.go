@@ -0,0 +1,71 @@
+package node
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+)
+
+func Point(s *sexpr.Sexpr) kicad.Point {
+	return kicad.Point{X: AtomFloat(s, 0), Y: AtomFloat(s, 1)}
+}
+
+func ChildPoint(s *sexpr.Sexpr, name string) kicad.Point {
+	return Point(Child(s, name))
+}
+
+func SetChildPoint(s *sexpr.Sexpr, name string, p kicad.Point) {
+	SetChild(s, name, Float(p.X), Float(p.Y))
+}
+
+func Position(s *sexpr.Sexpr) kicad.Position {
+	return kicad.Position{X: AtomFloat(s, 0), Y: AtomFloat(s, 1), Angle: AtomFloat(s, 2)}
+}
+
+func ChildPosition(s *sexpr.Sexpr, name string) kicad.Position {
+	return Position(Child(s, name))
+}
+
+// SetChildPosition writes (name X Y [ANGLE]), keeping a trailing keyword
+// such as "unlocked" if present.
+func SetChildPosition(s *sexpr.Sexpr, name string, p kicad.Position) {
+	old := AtomStrings(Child(s, name))
+	unlocked := len(old) > 0 && old[len(old)-1] == "unlocked"
+	hadAngle := len(old) > 2 && old[2] != "unlocked"
+
+	atoms := []*sexpr.SexprString{Float(p.X), Float(p.Y)}
+	if p.Angle != 0 || hadAngle {
+		atoms = append(atoms, Float(p.Angle))
+	}
+	if unlocked {
+		atoms = append(atoms, Sym("unlocked"))
+	}
+	SetChild(s, name, atoms...)
+}
+
+// Pts reads (pts (xy X Y) ...), ignoring arc segments.
+func Pts(s *sexpr.Sexpr) []kicad.Point {
+	points := []kicad.Point{}
+	for _, xy := range Children(Child(s, "pts"), "xy") {
+		points = append(points, Point(xy))
+	}
+	return points
+}
+
+func SetPts(s *sexpr.Sexpr, points []kicad.Point) {
+	pts := Child(s, "pts")
+	if pts == nil {
+		pts = New("pts")
+		Append(s, pts)
+	}
+	xys := Children(pts, "xy")
+	nodes := make([]*sexpr.Sexpr, len(points))
+	for i, p := range points {
+		if i < len(xys) {
+			nodes[i] = xys[i]
+			SetAtoms(xys[i], Float(p.X), Float(p.Y))
+		} else {
+			nodes[i] = New("xy", Float(p.X), Float(p.Y))
+		}
+	}
+	Sync(pts, []string{"xy"}, nodes)
+}
@@ -0,0 +1,77 @@
+package node
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Read parses a single document and checks its root is one of names.
+// Backslashes in quoted strings escape the rune that follows, as KiCad
+// writes them.
+func Read(r io.Reader, names ...string) (*sexpr.Sexpr, error) {
+	lexer := sexpr.NewLexer(bufio.NewReader(r))
+	lexer.SetEscapes(true)
+	root, err := sexpr.ParseLexer(lexer)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, errors.New("empty document")
+	}
+	if err := CheckRoot(root, names...); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func CheckRoot(root *sexpr.Sexpr, names ...string) error {
+	if len(names) > 0 && !hasName(names, root.Name()) {
+		return fmt.Errorf("unexpected root '%s' at Line %d, Column %d, expected '%s'", root.Name(), line(root), col(root), names[0])
+	}
+	return nil
+}
+
+func line(s *sexpr.Sexpr) int {
+	l, _ := s.Location()
+	return l
+}
+
+func col(s *sexpr.Sexpr) int {
+	_, c := s.Location()
+	return c
+}
+
+func Write(w io.Writer, root *sexpr.Sexpr) error {
+	_, err := io.WriteString(w, root.String()+"\n")
+	return err
+}
+
+func ReadFile(path string, names ...string) (*sexpr.Sexpr, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, err := Read(f, names...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return root, nil
+}
+
+func WriteFile(path string, root *sexpr.Sexpr) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(f, root); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,392 @@
+// Package node holds the helpers shared by the KiCad document models for
+// reading typed values out of, and writing them back into, a Sexpr tree.
+package node
+
+import (
+	"strconv"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+func Child(s *sexpr.Sexpr, name string) *sexpr.Sexpr {
+	if s == nil {
+		return nil
+	}
+	return s.FindDirectChildByNameExact(name)
+}
+
+func Children(s *sexpr.Sexpr, name string) []*sexpr.Sexpr {
+	if s == nil {
+		return nil
+	}
+	return s.FindDirectChildrenByNameExact(name)
+}
+
+func ChildSexprs(s *sexpr.Sexpr) []*sexpr.Sexpr {
+	children := []*sexpr.Sexpr{}
+	if s == nil {
+		return children
+	}
+	for _, param := range s.Params() {
+		if child, ok := param.Value().(*sexpr.Sexpr); ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func Atoms(s *sexpr.Sexpr) []*sexpr.SexprString {
+	atoms := []*sexpr.SexprString{}
+	if s == nil {
+		return atoms
+	}
+	for _, param := range s.Params() {
+		if ss, ok := param.Value().(*sexpr.SexprString); ok {
+			atoms = append(atoms, ss)
+		}
+	}
+	return atoms
+}
+
+// Atom returns the unescaped value of the idx'th string param of s, or ""
+// when there is none.
+func Atom(s *sexpr.Sexpr, idx int) string {
+	atoms := Atoms(s)
+	if idx < 0 || idx >= len(atoms) {
+		return ""
+	}
+	return Value(atoms[idx])
+}
+
+func AtomFloat(s *sexpr.Sexpr, idx int) float64 {
+	f, _ := strconv.ParseFloat(Atom(s, idx), 64)
+	return f
+}
+
+func AtomInt(s *sexpr.Sexpr, idx int) int {
+	i, _ := strconv.Atoi(Atom(s, idx))
+	return i
+}
+
+func AtomStrings(s *sexpr.Sexpr) []string {
+	values := []string{}
+	for _, atom := range Atoms(s) {
+		values = append(values, Value(atom))
+	}
+	return values
+}
+
+func ChildAtom(s *sexpr.Sexpr, name string, idx int) string {
+	return Atom(Child(s, name), idx)
+}
+
+func ChildFloat(s *sexpr.Sexpr, name string, idx int) float64 {
+	return AtomFloat(Child(s, name), idx)
+}
+
+func ChildInt(s *sexpr.Sexpr, name string, idx int) int {
+	return AtomInt(Child(s, name), idx)
+}
+
+func HasAtom(s *sexpr.Sexpr, v string) bool {
+	for _, atom := range Atoms(s) {
+		if !atom.Quoted() && atom.Value() == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag reports whether s carries the named flag, either as a bare atom
+// (KiCad 6/7 style "hide") or as a child ("(hide yes)" in KiCad 8).
+func Flag(s *sexpr.Sexpr, name string) bool {
+	if child := Child(s, name); child != nil {
+		v := Atom(child, 0)
+		return v == "" || v == "yes"
+	}
+	return HasAtom(s, name)
+}
+
+func SetFlag(s *sexpr.Sexpr, name string, v bool) {
+	if child := Child(s, name); child != nil {
+		if len(Atoms(child)) == 0 {
+			if !v {
+				RemoveChildren(s, name)
+			}
+			return
+		}
+		SetAtoms(child, Sym(yesNo(v)))
+		return
+	}
+	if HasAtom(s, name) {
+		if !v {
+			for i, param := range s.Params() {
+				if ss, ok := param.Value().(*sexpr.SexprString); ok && !ss.Quoted() && ss.Value() == name {
+					s.RemoveParam(i, param)
+					return
+				}
+			}
+		}
+		return
+	}
+	if v {
+		Append(s, New(name, Sym("yes")))
+	}
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// Value unescapes the value of a quoted string as KiCad writes it.
+func Value(ss *sexpr.SexprString) string {
+	if !ss.Quoted() {
+		return ss.Value()
+	}
+	return sexpr.Unescape(ss.Value())
+}
+
+// Str returns a quoted, escaped string atom.
+func Str(v string) *sexpr.SexprString {
+	return sexpr.NewSexprStringQuoted(sexpr.Escape(v), true)
+}
+
+// Sym returns a bare keyword atom.
+func Sym(v string) *sexpr.SexprString {
+	return sexpr.NewSexprStringQuoted(v, false)
+}
+
+func Float(f float64) *sexpr.SexprString {
+	return Sym(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func Int(i int) *sexpr.SexprString {
+	return Sym(strconv.Itoa(i))
+}
+
+// New builds a sexpr from *sexpr.SexprString and *sexpr.Sexpr params; nil
+// params are skipped.
+func New(name string, params ...any) *sexpr.Sexpr {
+	s := sexpr.NewSexpr(name)
+	for _, p := range params {
+		Append(s, p)
+	}
+	return s
+}
+
+func Append(s *sexpr.Sexpr, v any) {
+	switch pv := v.(type) {
+	case *sexpr.Sexpr:
+		if pv == nil {
+			return
+		}
+	case *sexpr.SexprString:
+		if pv == nil {
+			return
+		}
+	}
+	param, err := sexpr.NewSexprParam(v)
+	if err != nil {
+		return
+	}
+	s.AddParam(len(s.Params()), param)
+}
+
+func Insert(s *sexpr.Sexpr, idx int, v any) {
+	param, err := sexpr.NewSexprParam(v)
+	if err != nil {
+		return
+	}
+	s.AddParam(idx, param)
+}
+
+// sameAtom reports whether replacing old with new would leave the value
+// unchanged, so the original spelling can be kept.
+func sameAtom(old *sexpr.SexprString, new *sexpr.SexprString) bool {
+	if Value(old) == Value(new) {
+		return true
+	}
+	if old.Quoted() || new.Quoted() {
+		return false
+	}
+	fo, err := strconv.ParseFloat(old.Value(), 64)
+	if err != nil {
+		return false
+	}
+	fn, err := strconv.ParseFloat(new.Value(), 64)
+	if err != nil {
+		return false
+	}
+	return fo == fn
+}
+
+// SetAtoms replaces the string params of s with atoms, keeping nested
+// sexprs in place. Atoms whose value is unchanged keep their original form.
+func SetAtoms(s *sexpr.Sexpr, atoms ...*sexpr.SexprString) {
+	n := 0
+	for i := 0; i < len(s.Params()); i++ {
+		param := s.Params()[i]
+		old, ok := param.Value().(*sexpr.SexprString)
+		if !ok {
+			if n < len(atoms) {
+				for _, atom := range atoms[n:] {
+					Insert(s, i, atom)
+					i += 1
+				}
+				n = len(atoms)
+			}
+			continue
+		}
+		if n >= len(atoms) {
+			s.RemoveParam(i, param)
+			i -= 1
+			continue
+		}
+		if !sameAtom(old, atoms[n]) {
+			param.SetValue(atoms[n])
+		}
+		n += 1
+	}
+	for _, atom := range atoms[n:] {
+		Append(s, atom)
+	}
+}
+
+// SetAtom replaces the idx'th string param of s, or inserts it after the
+// last string param when idx is the number of string params.
+func SetAtom(s *sexpr.Sexpr, idx int, atom *sexpr.SexprString) {
+	n := 0
+	last := -1
+	for i, param := range s.Params() {
+		old, ok := param.Value().(*sexpr.SexprString)
+		if !ok {
+			continue
+		}
+		if n == idx {
+			if !sameAtom(old, atom) {
+				param.SetValue(atom)
+			}
+			return
+		}
+		n += 1
+		last = i
+	}
+	if idx == n {
+		Insert(s, last+1, atom)
+	}
+}
+
+// SetChild finds or creates the named direct child of s and sets its atoms.
+func SetChild(s *sexpr.Sexpr, name string, atoms ...*sexpr.SexprString) *sexpr.Sexpr {
+	child := Child(s, name)
+	if child == nil {
+		child = New(name)
+		Append(s, child)
+	}
+	SetAtoms(child, atoms...)
+	return child
+}
+
+// SetChildIf is SetChild when cond holds and RemoveChildren otherwise.
+func SetChildIf(s *sexpr.Sexpr, cond bool, name string, atoms ...*sexpr.SexprString) {
+	if cond {
+		SetChild(s, name, atoms...)
+	} else {
+		RemoveChildren(s, name)
+	}
+}
+
+func RemoveChildren(s *sexpr.Sexpr, name string) {
+	for i := 0; i < len(s.Params()); i++ {
+		param := s.Params()[i]
+		if child, ok := param.Value().(*sexpr.Sexpr); ok && child.Name() == name {
+			s.RemoveParam(i, param)
+			i -= 1
+		}
+	}
+}
+
+// Sync makes the direct children of parent named in names (or all children
+// when names is nil) exactly nodes.
+// Nodes already present keep their position, children not in nodes are
+// removed, and new nodes are inserted after the last kept one (or appended).
+func Sync(parent *sexpr.Sexpr, names []string, nodes []*sexpr.Sexpr) {
+	wanted := map[*sexpr.Sexpr]bool{}
+	for _, n := range nodes {
+		wanted[n] = true
+	}
+	present := map[*sexpr.Sexpr]bool{}
+	insertAt := -1
+	for i := 0; i < len(parent.Params()); i++ {
+		param := parent.Params()[i]
+		child, ok := param.Value().(*sexpr.Sexpr)
+		if !ok || (names != nil && !hasName(names, child.Name())) {
+			continue
+		}
+		if !wanted[child] {
+			parent.RemoveParam(i, param)
+			i -= 1
+			continue
+		}
+		present[child] = true
+		insertAt = i + 1
+	}
+	if insertAt == -1 {
+		insertAt = len(parent.Params())
+	}
+	for _, n := range nodes {
+		if present[n] {
+			continue
+		}
+		Insert(parent, insertAt, n)
+		insertAt += 1
+	}
+}
+
+func hasName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UUID returns the value of the (uuid ...) or, for older files, (tstamp ...)
+// child of s.
+func UUID(s *sexpr.Sexpr) string {
+	if uuid := Child(s, "uuid"); uuid != nil {
+		return Atom(uuid, 0)
+	}
+	return ChildAtom(s, "tstamp", 0)
+}
+
+func SetUUID(s *sexpr.Sexpr, v string) {
+	if Child(s, "uuid") == nil && Child(s, "tstamp") != nil {
+		SetChildIf(s, v != "", "tstamp", Sym(v))
+		return
+	}
+	SetChildIf(s, v != "", "uuid", Str(v))
+}
+
+// Clone returns a deep copy of s with no parent.
+func Clone(s *sexpr.Sexpr) *sexpr.Sexpr {
+	c := sexpr.NewSexpr(s.Name())
+	line, col := s.Location()
+	c.SetLocation(line, col)
+	for _, param := range s.Params() {
+		switch pv := param.Value().(type) {
+		case *sexpr.Sexpr:
+			Append(c, Clone(pv))
+		case *sexpr.SexprString:
+			ss := sexpr.NewSexprStringQuoted(pv.Value(), pv.Quoted())
+			line, col := pv.Location()
+			ss.SetLocation(line, col)
+			Append(c, ss)
+		}
+	}
+	return c
+}
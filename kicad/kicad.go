@@ -0,0 +1,31 @@
+// Package kicad holds the types shared by the KiCad document models in its
+// subpackages.
+//
+// The models keep each typed value linked to the sexpr it was loaded from.
+// Saving writes the typed fields back into those sexprs, so tokens a model
+// does not cover are preserved as they were read.
+package kicad
+
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Position is a point with an optional rotation in degrees, as written by
+// (at X Y [ANGLE]).
+type Position struct {
+	X     float64
+	Y     float64
+	Angle float64
+}
+
+func (p Position) Point() Point {
+	return Point{X: p.X, Y: p.Y}
+}
+
+// Drill is a pad hole. Size.X is the diameter of round holes; oval holes
+// use both Size.X and Size.Y. A zero Size means no drill.
+type Drill struct {
+	Oval bool
+	Size Point
+}
@@ -0,0 +1,65 @@
+package libtable
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Resolver returns the value of a path variable such as KIPRJMOD or
+// KICAD8_FOOTPRINT_DIR.
+type Resolver func(name string) (string, bool)
+
+// EnvResolver resolves variables from the process environment, with vars
+// taking precedence.
+func EnvResolver(vars map[string]string) Resolver {
+	return func(name string) (string, bool) {
+		if v, ok := vars[name]; ok {
+			return v, true
+		}
+		return os.LookupEnv(name)
+	}
+}
+
+// Expand replaces ${NAME} and $(NAME) references in s. It fails on the
+// first variable the resolver does not know.
+func Expand(s string, resolve Resolver) (string, error) {
+	var sb strings.Builder
+	for {
+		start := strings.IndexByte(s, '$')
+		if start == -1 {
+			sb.WriteString(s)
+			return sb.String(), nil
+		}
+		if start+1 >= len(s) || (s[start+1] != '{' && s[start+1] != '(') {
+			sb.WriteString(s[:start+1])
+			s = s[start+1:]
+			continue
+		}
+		closer := "}"
+		if s[start+1] == '(' {
+			closer = ")"
+		}
+		end := strings.Index(s[start+2:], closer)
+		if end == -1 {
+			return "", fmt.Errorf("unterminated variable reference in '%s'", s)
+		}
+		name := s[start+2 : start+2+end]
+		v, ok := resolve(name)
+		if !ok {
+			return "", fmt.Errorf("undefined variable '%s'", name)
+		}
+		sb.WriteString(s[:start])
+		sb.WriteString(v)
+		s = s[start+2+end+1:]
+	}
+}
+
+// ExpandedURI returns the entry's URI with path variables expanded.
+func (e *Entry) ExpandedURI(resolve Resolver) (string, error) {
+	uri, err := Expand(e.URI, resolve)
+	if err != nil {
+		return "", fmt.Errorf("library '%s': %w", e.Name, err)
+	}
+	return uri, nil
+}
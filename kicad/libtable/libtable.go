@@ -0,0 +1,191 @@
+// Package libtable reads and writes KiCad library tables (fp-lib-table and
+// sym-lib-table).
+package libtable
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+const (
+	KindFootprint = "fp_lib_table"
+	KindSymbol    = "sym_lib_table"
+)
+
+type LibTable struct {
+	Kind    string
+	Version int
+	Entries []*Entry
+
+	root *sexpr.Sexpr
+}
+
+// Entry is a (lib ...) row. Options and Descr are free-form strings as
+// KiCad stores them.
+type Entry struct {
+	Name     string
+	Type     string
+	URI      string
+	Options  string
+	Descr    string
+	Disabled bool
+	Hidden   bool
+
+	node *sexpr.Sexpr
+}
+
+func NewLibTable(kind string) *LibTable {
+	return &LibTable{Kind: kind, Entries: []*Entry{}, root: node.New(kind)}
+}
+
+func Load(r io.Reader) (*LibTable, error) {
+	root, err := node.Read(r, KindFootprint, KindSymbol)
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*LibTable, error) {
+	root, err := node.ReadFile(path, KindFootprint, KindSymbol)
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*LibTable, error) {
+	if err := node.CheckRoot(root, KindFootprint, KindSymbol); err != nil {
+		return nil, err
+	}
+	lt := LibTable{
+		Kind:    root.Name(),
+		Version: node.ChildInt(root, "version", 0),
+		Entries: []*Entry{},
+		root:    root,
+	}
+	for _, lib := range node.Children(root, "lib") {
+		lt.Entries = append(lt.Entries, &Entry{
+			Name:     node.ChildAtom(lib, "name", 0),
+			Type:     node.ChildAtom(lib, "type", 0),
+			URI:      node.ChildAtom(lib, "uri", 0),
+			Options:  node.ChildAtom(lib, "options", 0),
+			Descr:    node.ChildAtom(lib, "descr", 0),
+			Disabled: node.Child(lib, "disabled") != nil,
+			Hidden:   node.Child(lib, "hidden") != nil,
+			node:     lib,
+		})
+	}
+	return &lt, nil
+}
+
+func (lt *LibTable) Entry(name string) *Entry {
+	for _, e := range lt.Entries {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+func (lt *LibTable) Add(e *Entry) error {
+	if lt.Entry(e.Name) != nil {
+		return fmt.Errorf("library '%s' already exists", e.Name)
+	}
+	lt.Entries = append(lt.Entries, e)
+	return nil
+}
+
+func (lt *LibTable) Remove(name string) bool {
+	for i, e := range lt.Entries {
+		if e.Name == name {
+			lt.Entries = append(lt.Entries[:i], lt.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Sexpr writes the entries back into the underlying tree and returns its
+// root.
+func (lt *LibTable) Sexpr() *sexpr.Sexpr {
+	root := lt.root
+	if root.Name() != lt.Kind {
+		root.SetName(lt.Kind)
+	}
+	node.SetChildIf(root, lt.Version != 0, "version", node.Int(lt.Version))
+	nodes := []*sexpr.Sexpr{}
+	for _, e := range lt.Entries {
+		nodes = append(nodes, e.sync())
+	}
+	node.Sync(root, []string{"lib"}, nodes)
+	return root
+}
+
+// Save writes the table in KiCad's own layout, one library per line, so
+// that editing an entry only changes that entry's line.
+func (lt *LibTable) Save(w io.Writer) error {
+	root := lt.Sexpr()
+	var sb strings.Builder
+	sb.WriteString("(" + root.Name() + "\n")
+	for _, param := range root.Params() {
+		sb.WriteString("  ")
+		if child, ok := param.Value().(*sexpr.Sexpr); ok && child.Name() == "lib" {
+			sb.WriteString("(lib ")
+			for _, p := range child.Params() {
+				sb.WriteString(p.String())
+			}
+			sb.WriteString(")")
+		} else {
+			sb.WriteString(param.String())
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(")\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (lt *LibTable) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := lt.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (e *Entry) Node() *sexpr.Sexpr {
+	return e.node
+}
+
+func (e *Entry) sync() *sexpr.Sexpr {
+	if e.node == nil {
+		e.node = node.New("lib")
+	}
+	s := e.node
+	node.SetChild(s, "name", node.Str(e.Name))
+	node.SetChild(s, "type", node.Str(e.Type))
+	node.SetChild(s, "uri", node.Str(e.URI))
+	node.SetChild(s, "options", node.Str(e.Options))
+	node.SetChild(s, "descr", node.Str(e.Descr))
+	setMarker(s, "disabled", e.Disabled)
+	setMarker(s, "hidden", e.Hidden)
+	return s
+}
+
+func setMarker(s *sexpr.Sexpr, name string, v bool) {
+	if !v {
+		node.RemoveChildren(s, name)
+	} else if node.Child(s, name) == nil {
+		node.Append(s, node.New(name))
+	}
+}
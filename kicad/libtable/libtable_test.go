@@ -0,0 +1,111 @@
+package libtable
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	lt, err := LoadFile("testdata/fp-lib-table")
+	require.NoError(t, err)
+	require.Equal(t, KindFootprint, lt.Kind)
+	require.Equal(t, 7, lt.Version)
+	require.Equal(t, 3, len(lt.Entries))
+
+	e := lt.Entry("Connector")
+	require.NotNil(t, e)
+	require.Equal(t, "KiCad", e.Type)
+	require.Equal(t, "Generic connectors", e.Descr)
+	require.True(t, e.Disabled)
+	require.False(t, e.Hidden)
+	require.True(t, lt.Entry("Legacy").Hidden)
+
+	lt, err = LoadFile("testdata/sym-lib-table")
+	require.NoError(t, err)
+	require.Equal(t, KindSymbol, lt.Kind)
+	require.Equal(t, `Resistors, capacitors, "etc"`, lt.Entry("Device").Descr)
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	for _, name := range []string{"fp-lib-table", "sym-lib-table"} {
+		data, err := os.ReadFile("testdata/" + name)
+		require.NoError(t, err)
+		lt, err := Load(strings.NewReader(string(data)))
+		require.NoError(t, err)
+
+		var sb strings.Builder
+		require.NoError(t, lt.Save(&sb))
+		require.Equal(t, string(data), sb.String())
+	}
+}
+
+func TestEdit(t *testing.T) {
+	lt, err := LoadFile("testdata/fp-lib-table")
+	require.NoError(t, err)
+
+	lt.Entry("Connector").Disabled = false
+	require.True(t, lt.Remove("Legacy"))
+	require.False(t, lt.Remove("Legacy"))
+	require.NoError(t, lt.Add(&Entry{Name: "Vendor", Type: "KiCad", URI: "${KIPRJMOD}/libs/Vendor.pretty"}))
+	require.ErrorContains(t, lt.Add(&Entry{Name: "Local"}), "already exists")
+
+	var sb strings.Builder
+	require.NoError(t, lt.Save(&sb))
+	require.Equal(t, `(fp_lib_table
+  (version 7)
+  (lib (name "Local")(type "KiCad")(uri "${KIPRJMOD}/Local.pretty")(options "")(descr "Project footprints"))
+  (lib (name "Connector")(type "KiCad")(uri "${KICAD8_FOOTPRINT_DIR}/Connector.pretty")(options "")(descr "Generic connectors"))
+  (lib (name "Vendor")(type "KiCad")(uri "${KIPRJMOD}/libs/Vendor.pretty")(options "")(descr ""))
+)
+`, sb.String())
+}
+
+func TestNewLibTable(t *testing.T) {
+	lt := NewLibTable(KindSymbol)
+	lt.Version = 7
+	require.NoError(t, lt.Add(&Entry{Name: "Mine", Type: "KiCad", URI: "/libs/mine.kicad_sym"}))
+
+	var sb strings.Builder
+	require.NoError(t, lt.Save(&sb))
+	require.Equal(t, "(sym_lib_table\n  (version 7)\n  (lib (name \"Mine\")(type \"KiCad\")(uri \"/libs/mine.kicad_sym\")(options \"\")(descr \"\"))\n)\n", sb.String())
+}
+
+func TestExpand(t *testing.T) {
+	resolve := func(name string) (string, bool) {
+		switch name {
+		case "KIPRJMOD":
+			return "/home/me/proj", true
+		case "KICAD8_FOOTPRINT_DIR":
+			return "/usr/share/kicad/footprints", true
+		}
+		return "", false
+	}
+
+	lt, err := LoadFile("testdata/fp-lib-table")
+	require.NoError(t, err)
+
+	uri, err := lt.Entry("Local").ExpandedURI(resolve)
+	require.NoError(t, err)
+	require.Equal(t, "/home/me/proj/Local.pretty", uri)
+
+	uri, err = lt.Entry("Legacy").ExpandedURI(resolve)
+	require.NoError(t, err)
+	require.Equal(t, "/home/me/proj/legacy.mod", uri)
+
+	_, err = Expand("${NOPE}/x", resolve)
+	require.ErrorContains(t, err, "undefined variable 'NOPE'")
+
+	_, err = Expand("${KIPRJMOD", resolve)
+	require.ErrorContains(t, err, "unterminated")
+
+	s, err := Expand("cost $5 ${KIPRJMOD}", resolve)
+	require.NoError(t, err)
+	require.Equal(t, "cost $5 /home/me/proj", s)
+
+	s, err = Expand("${X}", EnvResolver(map[string]string{"X": "y"}))
+	require.NoError(t, err)
+	require.Equal(t, "y", s)
+}
@@ -0,0 +1,201 @@
+// Package netlist reads KiCad's s-expression netlist export (.net).
+package netlist
+
+import (
+	"io"
+	"strconv"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Netlist struct {
+	Version    string
+	Source     string
+	Date       string
+	Tool       string
+	Components []*Component
+	Nets       []*Net
+}
+
+type Component struct {
+	Ref       string
+	Value     string
+	Footprint string
+	Datasheet string
+	Fields    []Field
+	LibSource LibSource
+	SheetPath SheetPath
+	// Properties holds the (property (name ...) (value ...)) entries KiCad 7
+	// and later add alongside fields.
+	Properties []Field
+	Tstamps    string
+}
+
+type Field struct {
+	Name  string
+	Value string
+}
+
+type LibSource struct {
+	Lib         string
+	Part        string
+	Description string
+}
+
+type SheetPath struct {
+	Names   string
+	Tstamps string
+}
+
+type Net struct {
+	Code  int
+	Name  string
+	Nodes []NetNode
+}
+
+type NetNode struct {
+	Ref         string
+	Pin         string
+	PinFunction string
+	PinType     string
+}
+
+func Load(r io.Reader) (*Netlist, error) {
+	root, err := node.Read(r, "export")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Netlist, error) {
+	root, err := node.ReadFile(path, "export")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Netlist, error) {
+	if err := node.CheckRoot(root, "export"); err != nil {
+		return nil, err
+	}
+	design := root.FindDirectChildByNameExact("design")
+	nl := Netlist{
+		Version:    node.ChildAtom(root, "version", 0),
+		Source:     node.ChildAtom(design, "source", 0),
+		Date:       node.ChildAtom(design, "date", 0),
+		Tool:       node.ChildAtom(design, "tool", 0),
+		Components: []*Component{},
+		Nets:       []*Net{},
+	}
+	if components := root.FindDirectChildByNameExact("components"); components != nil {
+		for _, comp := range components.FindDirectChildrenByNameExact("comp") {
+			nl.Components = append(nl.Components, newComponent(comp))
+		}
+	}
+	if nets := root.FindDirectChildByNameExact("nets"); nets != nil {
+		for _, net := range nets.FindDirectChildrenByNameExact("net") {
+			nl.Nets = append(nl.Nets, newNet(net))
+		}
+	}
+	return &nl, nil
+}
+
+func newComponent(s *sexpr.Sexpr) *Component {
+	c := Component{
+		Ref:        node.ChildAtom(s, "ref", 0),
+		Value:      node.ChildAtom(s, "value", 0),
+		Footprint:  node.ChildAtom(s, "footprint", 0),
+		Datasheet:  node.ChildAtom(s, "datasheet", 0),
+		Fields:     []Field{},
+		Properties: []Field{},
+		Tstamps:    node.ChildAtom(s, "tstamps", 0),
+	}
+	if fields := s.FindDirectChildByNameExact("fields"); fields != nil {
+		for _, f := range fields.FindDirectChildrenByNameExact("field") {
+			c.Fields = append(c.Fields, Field{Name: node.ChildAtom(f, "name", 0), Value: node.Atom(f, 0)})
+		}
+	}
+	if lib := s.FindDirectChildByNameExact("libsource"); lib != nil {
+		c.LibSource = LibSource{
+			Lib:         node.ChildAtom(lib, "lib", 0),
+			Part:        node.ChildAtom(lib, "part", 0),
+			Description: node.ChildAtom(lib, "description", 0),
+		}
+	}
+	if sheet := s.FindDirectChildByNameExact("sheetpath"); sheet != nil {
+		c.SheetPath = SheetPath{
+			Names:   node.ChildAtom(sheet, "names", 0),
+			Tstamps: node.ChildAtom(sheet, "tstamps", 0),
+		}
+	}
+	for _, p := range s.FindDirectChildrenByNameExact("property") {
+		c.Properties = append(c.Properties, Field{Name: node.ChildAtom(p, "name", 0), Value: node.ChildAtom(p, "value", 0)})
+	}
+	return &c
+}
+
+func newNet(s *sexpr.Sexpr) *Net {
+	code, _ := strconv.Atoi(node.ChildAtom(s, "code", 0))
+	n := Net{
+		Code:  code,
+		Name:  node.ChildAtom(s, "name", 0),
+		Nodes: []NetNode{},
+	}
+	for _, nn := range s.FindDirectChildrenByNameExact("node") {
+		n.Nodes = append(n.Nodes, NetNode{
+			Ref:         node.ChildAtom(nn, "ref", 0),
+			Pin:         node.ChildAtom(nn, "pin", 0),
+			PinFunction: node.ChildAtom(nn, "pinfunction", 0),
+			PinType:     node.ChildAtom(nn, "pintype", 0),
+		})
+	}
+	return &n
+}
+
+func (nl *Netlist) Component(ref string) *Component {
+	for _, c := range nl.Components {
+		if c.Ref == ref {
+			return c
+		}
+	}
+	return nil
+}
+
+func (nl *Netlist) Net(name string) *Net {
+	for _, n := range nl.Nets {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+// PinNet returns the net connected to a component pin.
+func (nl *Netlist) PinNet(ref string, pin string) *Net {
+	for _, n := range nl.Nets {
+		for _, nn := range n.Nodes {
+			if nn.Ref == ref && nn.Pin == pin {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
+// Field returns a component field by name, falling back to its properties.
+func (c *Component) Field(name string) (string, bool) {
+	for _, f := range c.Fields {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	for _, f := range c.Properties {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+	return "", false
+}
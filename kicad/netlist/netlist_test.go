@@ -0,0 +1,69 @@
+package netlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	nl, err := LoadFile("testdata/demo.net")
+	require.NoError(t, err)
+
+	require.Equal(t, "E", nl.Version)
+	require.Equal(t, "Eeschema 8.0.2", nl.Tool)
+	require.Equal(t, "/home/me/demo/demo.kicad_sch", nl.Source)
+
+	require.Equal(t, 2, len(nl.Components))
+	r1 := nl.Component("R1")
+	require.NotNil(t, r1)
+	require.Equal(t, "10k", r1.Value)
+	require.Equal(t, "Resistor_SMD:R_0603_1608Metric", r1.Footprint)
+	require.Equal(t, LibSource{Lib: "Device", Part: "R", Description: "Resistor"}, r1.LibSource)
+	require.Equal(t, SheetPath{Names: "/", Tstamps: "/"}, r1.SheetPath)
+	require.Equal(t, 3, len(r1.Fields))
+	v, ok := r1.Field("MPN")
+	require.True(t, ok)
+	require.Equal(t, "RC0603FR-0710KL", v)
+	v, ok = r1.Field("Sheetfile")
+	require.True(t, ok)
+	require.Equal(t, "demo.kicad_sch", v)
+	_, ok = r1.Field("Nope")
+	require.False(t, ok)
+
+	u1 := nl.Component("U1")
+	require.Equal(t, 0, len(u1.Fields))
+	require.Equal(t, "0a1b2c3d-0000-4000-8000-000000000009", u1.Tstamps)
+
+	require.Equal(t, 2, len(nl.Nets))
+	gnd := nl.Net("GND")
+	require.Equal(t, 1, gnd.Code)
+	require.Equal(t, []NetNode{
+		{Ref: "R1", Pin: "2", PinType: "passive"},
+		{Ref: "U1", Pin: "1", PinFunction: "GND", PinType: "power_in"},
+	}, gnd.Nodes)
+	require.Equal(t, "/VOUT", nl.PinNet("U1", "2").Name)
+	require.Nil(t, nl.PinNet("U1", "3"))
+}
+
+func TestLoadLegacy(t *testing.T) {
+	nl, err := Load(strings.NewReader(`(export (version D)
+  (components
+    (comp (ref C1) (value 100n)
+      (fields (field (name MPN) GRM155)))
+  )
+  (nets
+    (net (code 1) (name GND)
+      (node (ref C1) (pin 2)))))`))
+	require.NoError(t, err)
+	require.Equal(t, "D", nl.Version)
+	v, _ := nl.Component("C1").Field("MPN")
+	require.Equal(t, "GRM155", v)
+	require.Equal(t, "GND", nl.PinNet("C1", "2").Name)
+}
+
+func TestLoadWrongRoot(t *testing.T) {
+	_, err := Load(strings.NewReader(`(kicad_pcb)`))
+	require.ErrorContains(t, err, "unexpected root")
+}
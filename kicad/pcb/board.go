@@ -0,0 +1,171 @@
+// Package pcb maps KiCad board files (.kicad_pcb) onto typed structures.
+//
+// A Board holds the layer table, nets, footprints and their pads, tracks
+// (segments, arcs and vias), zones and board graphics. Board setup, the
+// title block and dimensions are not modelled.
+package pcb
+
+import (
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Board struct {
+	Version    int
+	Generator  string
+	Layers     []*Layer
+	Nets       []*Net
+	Footprints []*Footprint
+	Tracks     []*Track
+	Zones      []*Zone
+	Graphics   []*Graphic
+
+	root *sexpr.Sexpr
+}
+
+var boardGraphicNames = []string{"gr_line", "gr_arc", "gr_circle", "gr_rect", "gr_poly", "gr_curve", "gr_text"}
+
+func NewBoard() *Board {
+	return &Board{root: node.New("kicad_pcb")}
+}
+
+func Load(r io.Reader) (*Board, error) {
+	root, err := node.Read(r, "kicad_pcb")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Board, error) {
+	root, err := node.ReadFile(path, "kicad_pcb")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Board, error) {
+	if err := node.CheckRoot(root, "kicad_pcb"); err != nil {
+		return nil, err
+	}
+	b := Board{
+		Version:    node.ChildInt(root, "version", 0),
+		Generator:  node.ChildAtom(root, "generator", 0),
+		Layers:     []*Layer{},
+		Nets:       []*Net{},
+		Footprints: []*Footprint{},
+		Tracks:     []*Track{},
+		Zones:      []*Zone{},
+		Graphics:   []*Graphic{},
+		root:       root,
+	}
+	for _, l := range node.ChildSexprs(node.Child(root, "layers")) {
+		b.Layers = append(b.Layers, newLayer(l))
+	}
+	for _, child := range node.ChildSexprs(root) {
+		switch child.Name() {
+		case "net":
+			b.Nets = append(b.Nets, newNet(child))
+		case "footprint", "module":
+			b.Footprints = append(b.Footprints, newFootprint(child))
+		case "segment", "arc", "via":
+			b.Tracks = append(b.Tracks, newTrack(child))
+		case "zone":
+			b.Zones = append(b.Zones, newZone(child))
+		case "gr_line", "gr_arc", "gr_circle", "gr_rect", "gr_poly", "gr_curve", "gr_text":
+			b.Graphics = append(b.Graphics, newGraphic(child))
+		}
+	}
+	return &b, nil
+}
+
+// Sexpr writes the typed fields back into the underlying tree and returns
+// its root.
+func (b *Board) Sexpr() *sexpr.Sexpr {
+	root := b.root
+	node.SetChildIf(root, b.Version != 0, "version", node.Int(b.Version))
+	node.SetChildIf(root, b.Generator != "", "generator", node.Str(b.Generator))
+
+	layers := node.Child(root, "layers")
+	if layers == nil && len(b.Layers) > 0 {
+		layers = node.New("layers")
+		node.Append(root, layers)
+	}
+	if layers != nil {
+		nodes := []*sexpr.Sexpr{}
+		for _, l := range b.Layers {
+			nodes = append(nodes, l.sync())
+		}
+		node.Sync(layers, nil, nodes)
+	}
+
+	nodes := []*sexpr.Sexpr{}
+	for _, n := range b.Nets {
+		nodes = append(nodes, n.sync())
+	}
+	node.Sync(root, []string{"net"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, fp := range b.Footprints {
+		nodes = append(nodes, fp.sync())
+	}
+	node.Sync(root, []string{"footprint", "module"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, g := range b.Graphics {
+		nodes = append(nodes, g.sync())
+	}
+	node.Sync(root, boardGraphicNames, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, t := range b.Tracks {
+		nodes = append(nodes, t.sync())
+	}
+	node.Sync(root, []string{"segment", "arc", "via"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, z := range b.Zones {
+		nodes = append(nodes, z.sync())
+	}
+	node.Sync(root, []string{"zone"}, nodes)
+
+	return root
+}
+
+func (b *Board) Save(w io.Writer) error {
+	return node.Write(w, b.Sexpr())
+}
+
+func (b *Board) SaveFile(path string) error {
+	return node.WriteFile(path, b.Sexpr())
+}
+
+func (b *Board) Net(number int) *Net {
+	for _, n := range b.Nets {
+		if n.Number == number {
+			return n
+		}
+	}
+	return nil
+}
+
+func (b *Board) NetByName(name string) *Net {
+	for _, n := range b.Nets {
+		if n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func (b *Board) Footprint(reference string) *Footprint {
+	for _, fp := range b.Footprints {
+		if fp.Reference == reference {
+			return fp
+		}
+	}
+	return nil
+}
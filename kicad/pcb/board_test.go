@@ -0,0 +1,148 @@
+package pcb
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	b, err := LoadFile("testdata/board.kicad_pcb")
+	require.NoError(t, err)
+
+	require.Equal(t, 20240108, b.Version)
+	require.Equal(t, "pcbnew", b.Generator)
+
+	require.Equal(t, 4, len(b.Layers))
+	require.Equal(t, Layer{Ordinal: 37, Name: "F.SilkS", Type: "user", UserName: "F.Silkscreen"}, *withoutNode(b.Layers[2]))
+
+	require.Equal(t, 3, len(b.Nets))
+	require.Equal(t, "/VIN", b.Net(2).Name)
+	require.Equal(t, 1, b.NetByName("GND").Number)
+
+	require.Equal(t, 2, len(b.Footprints))
+	r1 := b.Footprint("R1")
+	require.NotNil(t, r1)
+	require.Equal(t, "Resistor_SMD:R_0603_1608Metric", r1.LibID)
+	require.Equal(t, "10k", r1.Value)
+	require.Equal(t, kicad.Position{X: 100, Y: 50, Angle: 90}, r1.At)
+	require.False(t, r1.Locked)
+	v, ok := r1.Property("Datasheet")
+	require.True(t, ok)
+	require.Equal(t, "~", v)
+	require.Equal(t, 1, len(r1.Graphics))
+	require.Equal(t, 0.12, r1.Graphics[0].Width)
+	require.Equal(t, 2, len(r1.Pads))
+	require.Equal(t, "2", r1.Pads[1].Number)
+	require.Equal(t, "roundrect", r1.Pads[1].Shape)
+	require.Equal(t, "/VIN", r1.Pads[1].NetName)
+	require.Equal(t, []string{"F.Cu", "F.Paste", "F.Mask"}, r1.Pads[1].Layers)
+
+	j1 := b.Footprint("J1")
+	require.NotNil(t, j1)
+	require.True(t, j1.Locked)
+	require.Equal(t, "Conn", j1.Value)
+	require.Equal(t, "5f0b7e61-93a4-4b7e-8b53-2c7b9e0d1a11", j1.UUID)
+	require.Equal(t, 1, len(j1.Graphics))
+	require.Equal(t, "${REFERENCE}", j1.Graphics[0].Text)
+	require.Equal(t, kicad.Drill{Oval: true, Size: kicad.Point{X: 1, Y: 1.2}}, j1.Pads[0].Drill)
+
+	require.Equal(t, 2, len(b.Graphics))
+	require.Equal(t, "gr_rect", b.Graphics[0].Kind)
+	require.Equal(t, kicad.Point{X: 130, Y: 60}, b.Graphics[0].End)
+	require.Equal(t, `rev "A"`, b.Graphics[1].Text)
+
+	require.Equal(t, 3, len(b.Tracks))
+	require.Equal(t, TrackSegment, b.Tracks[0].Kind)
+	require.Equal(t, TrackArc, b.Tracks[1].Kind)
+	require.Equal(t, kicad.Point{X: 105, Y: 45}, b.Tracks[1].Mid)
+	require.Equal(t, TrackVia, b.Tracks[2].Kind)
+	require.Equal(t, []string{"F.Cu", "B.Cu"}, b.Tracks[2].Layers)
+	require.Equal(t, 0.3, b.Tracks[2].Drill)
+
+	require.Equal(t, 1, len(b.Zones))
+	require.Equal(t, "ground", b.Zones[0].Name)
+	require.Equal(t, 4, len(b.Zones[0].Outline))
+}
+
+func TestLoadWrongRoot(t *testing.T) {
+	_, err := Load(strings.NewReader("(kicad_sch (version 1))"))
+	require.ErrorContains(t, err, "unexpected root 'kicad_sch'")
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/board.kicad_pcb")
+	require.NoError(t, err)
+	root, err := node.Read(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	b, err := Load(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, b.Save(&sb))
+	require.Equal(t, root.String()+"\n", sb.String())
+}
+
+func TestSaveModified(t *testing.T) {
+	b, err := LoadFile("testdata/board.kicad_pcb")
+	require.NoError(t, err)
+
+	r1 := b.Footprint("R1")
+	r1.Reference = "R10"
+	r1.SetProperty("MPN", "RC0603")
+	b.Footprint("J1").Value = "Pin"
+	b.Footprint("J1").Locked = false
+	b.Footprint("J1").Pads[0].Drill = kicad.Drill{Size: kicad.Point{X: 1, Y: 1}}
+	b.Nets = append(b.Nets, &Net{Number: 3, Name: "+3V3"})
+	b.Tracks = b.Tracks[:2]
+	b.Tracks[0].Width = 0.5
+	b.Graphics = append(b.Graphics, &Graphic{Kind: "gr_line", Start: kicad.Point{X: 1, Y: 2}, End: kicad.Point{X: 3, Y: 4}, Layer: "F.SilkS", Width: 0.1})
+	b.Footprints = append(b.Footprints, &Footprint{
+		LibID:     "TestPoint:TP",
+		Layer:     "F.Cu",
+		Reference: "TP1",
+		Value:     "TP",
+		At:        kicad.Position{X: 10, Y: 20},
+		Pads:      []*Pad{{Number: "1", Type: "smd", Shape: "circle", Size: kicad.Point{X: 1, Y: 1}, Layers: []string{"F.Cu"}}},
+	})
+
+	var sb strings.Builder
+	require.NoError(t, b.Save(&sb))
+	out := sb.String()
+
+	b, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	require.NotNil(t, b.Footprint("R10"))
+	v, _ := b.Footprint("R10").Property("MPN")
+	require.Equal(t, "RC0603", v)
+	require.Equal(t, "Pin", b.Footprint("J1").Value)
+	require.False(t, b.Footprint("J1").Locked)
+	require.Equal(t, kicad.Drill{Size: kicad.Point{X: 1, Y: 1}}, b.Footprint("J1").Pads[0].Drill)
+	require.Equal(t, "+3V3", b.Net(3).Name)
+	require.Equal(t, 2, len(b.Tracks))
+	require.Equal(t, 0.5, b.Tracks[0].Width)
+	require.Equal(t, 3, len(b.Graphics))
+	require.Equal(t, 0.1, b.Graphics[2].Width)
+	tp := b.Footprint("TP1")
+	require.NotNil(t, tp)
+	require.Equal(t, kicad.Position{X: 10, Y: 20}, tp.At)
+	require.Equal(t, "circle", tp.Pads[0].Shape)
+
+	// unknown tokens survive
+	require.Contains(t, out, "(legacy_teardrops no)")
+	require.Contains(t, out, "(roundrect_rratio 0.25)")
+	require.Contains(t, out, "(offset 0 0.1)")
+	require.Contains(t, out, "(hatch edge 0.5)")
+	require.NotContains(t, out, "(via")
+	require.NotContains(t, out, "locked")
+}
+
+func withoutNode(l *Layer) *Layer {
+	c := *l
+	c.node = nil
+	return &c
+}
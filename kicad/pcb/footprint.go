@@ -0,0 +1,122 @@
+package pcb
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Footprint is a footprint placed on the board. Its pads carry the nets
+// they connect to.
+type Footprint struct {
+	LibID      string
+	Layer      string
+	At         kicad.Position
+	Locked     bool
+	UUID       string
+	Reference  string
+	Value      string
+	Properties []*Property
+	Pads       []*Pad
+	Graphics   []*Graphic
+
+	node   *sexpr.Sexpr
+	fields node.Fields
+}
+
+type Property struct {
+	Name  string
+	Value string
+
+	node *sexpr.Sexpr
+}
+
+var footprintGraphicNames = []string{"fp_line", "fp_arc", "fp_circle", "fp_rect", "fp_poly", "fp_curve", "fp_text"}
+
+func newFootprint(s *sexpr.Sexpr) *Footprint {
+	fp := Footprint{
+		LibID:      node.Atom(s, 0),
+		Layer:      node.ChildAtom(s, "layer", 0),
+		At:         node.ChildPosition(s, "at"),
+		Locked:     node.Flag(s, "locked"),
+		UUID:       node.UUID(s),
+		Properties: []*Property{},
+		Pads:       []*Pad{},
+		Graphics:   []*Graphic{},
+		node:       s,
+	}
+	for _, child := range node.ChildSexprs(s) {
+		if fp.fields.Read(child, &fp.Reference, &fp.Value) {
+			continue
+		}
+		switch child.Name() {
+		case "property":
+			fp.Properties = append(fp.Properties, &Property{Name: node.Atom(child, 0), Value: node.Atom(child, 1), node: child})
+		case "pad":
+			fp.Pads = append(fp.Pads, newPad(child))
+		case "fp_text", "fp_line", "fp_arc", "fp_circle", "fp_rect", "fp_poly", "fp_curve":
+			fp.Graphics = append(fp.Graphics, newGraphic(child))
+		}
+	}
+	return &fp
+}
+
+func (fp *Footprint) Node() *sexpr.Sexpr {
+	return fp.node
+}
+
+func (fp *Footprint) Property(name string) (string, bool) {
+	for _, p := range fp.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+func (fp *Footprint) SetProperty(name string, value string) {
+	for _, p := range fp.Properties {
+		if p.Name == name {
+			p.Value = value
+			return
+		}
+	}
+	fp.Properties = append(fp.Properties, &Property{Name: name, Value: value})
+}
+
+func (fp *Footprint) sync() *sexpr.Sexpr {
+	if fp.node == nil {
+		fp.node = node.New("footprint")
+	}
+	s := fp.node
+	node.SetAtom(s, 0, node.Str(fp.LibID))
+	node.SetFlag(s, "locked", fp.Locked)
+	node.SetChild(s, "layer", node.Str(fp.Layer))
+	node.SetUUID(s, fp.UUID)
+	node.SetChildPosition(s, "at", fp.At)
+
+	properties, graphics := fp.fields.Sync(fp.Reference, fp.Value, func(name string) *sexpr.Sexpr {
+		return node.New("property", node.Str(name))
+	})
+	for _, p := range fp.Properties {
+		if p.node == nil {
+			p.node = node.New("property")
+		}
+		node.SetAtom(p.node, 0, node.Str(p.Name))
+		node.SetAtom(p.node, 1, node.Str(p.Value))
+		properties = append(properties, p.node)
+	}
+	node.Sync(s, []string{"property"}, properties)
+
+	for _, g := range fp.Graphics {
+		graphics = append(graphics, g.sync())
+	}
+	node.Sync(s, footprintGraphicNames, graphics)
+
+	pads := []*sexpr.Sexpr{}
+	for _, p := range fp.Pads {
+		pads = append(pads, p.sync())
+	}
+	node.Sync(s, []string{"pad"}, pads)
+	return s
+}
@@ -0,0 +1,123 @@
+package pcb
+
+import (
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Graphic is a board (gr_*) or footprint (fp_*) drawing. Kind is the token
+// name, e.g. gr_line or fp_circle, and selects which fields apply: Start and
+// End for lines and rects, Start, Mid and End for arcs, Center and End for
+// circles, Points for polys and curves, and Text and At for text.
+type Graphic struct {
+	Kind   string
+	Start  kicad.Point
+	Mid    kicad.Point
+	End    kicad.Point
+	Center kicad.Point
+	Points []kicad.Point
+	Text   string
+	At     kicad.Position
+	Layer  string
+	Width  float64
+	Fill   string
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+func newGraphic(s *sexpr.Sexpr) *Graphic {
+	g := Graphic{
+		Kind:   s.Name(),
+		Start:  node.ChildPoint(s, "start"),
+		Mid:    node.ChildPoint(s, "mid"),
+		End:    node.ChildPoint(s, "end"),
+		Center: node.ChildPoint(s, "center"),
+		Points: node.Pts(s),
+		At:     node.ChildPosition(s, "at"),
+		Layer:  node.ChildAtom(s, "layer", 0),
+		Width:  node.ChildFloat(s, "width", 0),
+		Fill:   node.ChildAtom(s, "fill", 0),
+		UUID:   node.UUID(s),
+		node:   s,
+	}
+	if stroke := node.Child(s, "stroke"); stroke != nil {
+		g.Width = node.ChildFloat(stroke, "width", 0)
+	}
+	if g.shape() == "text" {
+		g.Text = node.Atom(s, g.textIdx())
+	}
+	return &g
+}
+
+func (g *Graphic) Node() *sexpr.Sexpr {
+	return g.node
+}
+
+func (g *Graphic) shape() string {
+	return strings.TrimPrefix(strings.TrimPrefix(g.Kind, "gr_"), "fp_")
+}
+
+// textIdx is the position of the text atom: fp_text carries its type
+// (reference, value or user) first.
+func (g *Graphic) textIdx() int {
+	if g.Kind == "fp_text" {
+		return 1
+	}
+	return 0
+}
+
+func (g *Graphic) sync() *sexpr.Sexpr {
+	if g.node == nil || g.node.Name() != g.Kind {
+		g.node = node.New(g.Kind)
+		if g.Kind == "fp_text" {
+			node.Append(g.node, node.Sym("user"))
+		}
+	}
+	s := g.node
+	switch g.shape() {
+	case "line", "rect":
+		node.SetChildPoint(s, "start", g.Start)
+		node.SetChildPoint(s, "end", g.End)
+	case "arc":
+		node.SetChildPoint(s, "start", g.Start)
+		node.SetChildPoint(s, "mid", g.Mid)
+		node.SetChildPoint(s, "end", g.End)
+	case "circle":
+		node.SetChildPoint(s, "center", g.Center)
+		node.SetChildPoint(s, "end", g.End)
+	case "poly", "curve":
+		node.SetPts(s, g.Points)
+	case "text":
+		node.SetAtom(s, g.textIdx(), node.Str(g.Text))
+		node.SetChildPosition(s, "at", g.At)
+	}
+	node.SetChild(s, "layer", node.Str(g.Layer))
+	if g.shape() != "text" {
+		setWidth(s, g.Width)
+	}
+	node.SetChildIf(s, g.Fill != "", "fill", node.Sym(g.Fill))
+	node.SetUUID(s, g.UUID)
+	return s
+}
+
+// setWidth writes the line width into (stroke (width W)) or, for files that
+// predate strokes, (width W).
+func setWidth(s *sexpr.Sexpr, width float64) {
+	if node.Child(s, "width") != nil {
+		node.SetChild(s, "width", node.Float(width))
+		return
+	}
+	stroke := node.Child(s, "stroke")
+	if stroke == nil {
+		stroke = node.New("stroke")
+		node.Append(s, stroke)
+		node.SetChild(stroke, "width", node.Float(width))
+		node.SetChild(stroke, "type", node.Sym("solid"))
+		return
+	}
+	node.SetChild(stroke, "width", node.Float(width))
+}
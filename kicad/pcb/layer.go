@@ -0,0 +1,49 @@
+package pcb
+
+import (
+	"strconv"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Layer is an entry of the board's (layers ...) table, written as
+// (ORDINAL "NAME" TYPE ["USER_NAME"]).
+type Layer struct {
+	Ordinal  int
+	Name     string
+	Type     string
+	UserName string
+
+	node *sexpr.Sexpr
+}
+
+func newLayer(s *sexpr.Sexpr) *Layer {
+	ordinal, _ := strconv.Atoi(s.Name())
+	return &Layer{
+		Ordinal:  ordinal,
+		Name:     node.Atom(s, 0),
+		Type:     node.Atom(s, 1),
+		UserName: node.Atom(s, 2),
+		node:     s,
+	}
+}
+
+func (l *Layer) Node() *sexpr.Sexpr {
+	return l.node
+}
+
+func (l *Layer) sync() *sexpr.Sexpr {
+	if l.node == nil {
+		l.node = node.New(strconv.Itoa(l.Ordinal))
+	}
+	if l.node.Name() != strconv.Itoa(l.Ordinal) {
+		l.node.SetName(strconv.Itoa(l.Ordinal))
+	}
+	atoms := []*sexpr.SexprString{node.Str(l.Name), node.Sym(l.Type)}
+	if l.UserName != "" {
+		atoms = append(atoms, node.Str(l.UserName))
+	}
+	node.SetAtoms(l.node, atoms...)
+	return l.node
+}
@@ -0,0 +1,33 @@
+package pcb
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Net struct {
+	Number int
+	Name   string
+
+	node *sexpr.Sexpr
+}
+
+func newNet(s *sexpr.Sexpr) *Net {
+	return &Net{
+		Number: node.AtomInt(s, 0),
+		Name:   node.Atom(s, 1),
+		node:   s,
+	}
+}
+
+func (n *Net) Node() *sexpr.Sexpr {
+	return n.node
+}
+
+func (n *Net) sync() *sexpr.Sexpr {
+	if n.node == nil {
+		n.node = node.New("net")
+	}
+	node.SetAtoms(n.node, node.Int(n.Number), node.Str(n.Name))
+	return n.node
+}
@@ -0,0 +1,66 @@
+package pcb
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Pad is a pad of a placed footprint, with the net it connects to.
+type Pad struct {
+	Number      string
+	Type        string
+	Shape       string
+	At          kicad.Position
+	Size        kicad.Point
+	Drill       kicad.Drill
+	Layers      []string
+	NetNumber   int
+	NetName     string
+	PinFunction string
+	PinType     string
+	UUID        string
+
+	node *sexpr.Sexpr
+}
+
+func newPad(s *sexpr.Sexpr) *Pad {
+	shared := node.ReadPad(s)
+	return &Pad{
+		Number:      shared.Number,
+		Type:        shared.Type,
+		Shape:       shared.Shape,
+		At:          shared.At,
+		Size:        shared.Size,
+		Drill:       shared.Drill,
+		Layers:      shared.Layers,
+		NetNumber:   node.ChildInt(s, "net", 0),
+		NetName:     node.ChildAtom(s, "net", 1),
+		PinFunction: node.ChildAtom(s, "pinfunction", 0),
+		PinType:     node.ChildAtom(s, "pintype", 0),
+		UUID:        node.UUID(s),
+		node:        s,
+	}
+}
+
+func (p *Pad) Node() *sexpr.Sexpr {
+	return p.node
+}
+
+func (p *Pad) sync() *sexpr.Sexpr {
+	p.node = node.SyncPad(p.node, node.Pad{
+		Number: p.Number,
+		Type:   p.Type,
+		Shape:  p.Shape,
+		At:     p.At,
+		Size:   p.Size,
+		Drill:  p.Drill,
+		Layers: p.Layers,
+	})
+	s := p.node
+	node.SetChildIf(s, p.NetNumber != 0 || p.NetName != "", "net", node.Int(p.NetNumber), node.Str(p.NetName))
+	node.SetChildIf(s, p.PinFunction != "", "pinfunction", node.Str(p.PinFunction))
+	node.SetChildIf(s, p.PinType != "", "pintype", node.Str(p.PinType))
+	node.SetUUID(s, p.UUID)
+	return s
+}
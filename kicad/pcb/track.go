@@ -0,0 +1,86 @@
+package pcb
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Track is a copper segment, arc or via. Start, Mid, End, Width and Layer
+// apply to segments and arcs (Mid to arcs only); At, Size, Drill and Layers
+// apply to vias.
+type Track struct {
+	Kind   TrackKind
+	Start  kicad.Point
+	Mid    kicad.Point
+	End    kicad.Point
+	Width  float64
+	Layer  string
+	At     kicad.Point
+	Size   float64
+	Drill  float64
+	Layers []string
+	Net    int
+	Locked bool
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+func newTrack(s *sexpr.Sexpr) *Track {
+	t := Track{
+		Start:  node.ChildPoint(s, "start"),
+		Mid:    node.ChildPoint(s, "mid"),
+		End:    node.ChildPoint(s, "end"),
+		Width:  node.ChildFloat(s, "width", 0),
+		Layer:  node.ChildAtom(s, "layer", 0),
+		At:     node.ChildPoint(s, "at"),
+		Size:   node.ChildFloat(s, "size", 0),
+		Drill:  node.ChildFloat(s, "drill", 0),
+		Layers: node.AtomStrings(node.Child(s, "layers")),
+		Net:    node.ChildInt(s, "net", 0),
+		Locked: node.Flag(s, "locked"),
+		UUID:   node.UUID(s),
+		node:   s,
+	}
+	switch s.Name() {
+	case "arc":
+		t.Kind = TrackArc
+	case "via":
+		t.Kind = TrackVia
+	}
+	return &t
+}
+
+func (t *Track) Node() *sexpr.Sexpr {
+	return t.node
+}
+
+func (t *Track) sync() *sexpr.Sexpr {
+	if t.node == nil || t.node.Name() != t.Kind.String() {
+		t.node = node.New(t.Kind.String())
+	}
+	s := t.node
+	node.SetFlag(s, "locked", t.Locked)
+	if t.Kind == TrackVia {
+		node.SetChildPoint(s, "at", t.At)
+		node.SetChild(s, "size", node.Float(t.Size))
+		node.SetChildIf(s, t.Drill != 0, "drill", node.Float(t.Drill))
+		layers := []*sexpr.SexprString{}
+		for _, l := range t.Layers {
+			layers = append(layers, node.Str(l))
+		}
+		node.SetChild(s, "layers", layers...)
+	} else {
+		node.SetChildPoint(s, "start", t.Start)
+		if t.Kind == TrackArc {
+			node.SetChildPoint(s, "mid", t.Mid)
+		}
+		node.SetChildPoint(s, "end", t.End)
+		node.SetChild(s, "width", node.Float(t.Width))
+		node.SetChild(s, "layer", node.Str(t.Layer))
+	}
+	node.SetChild(s, "net", node.Int(t.Net))
+	node.SetUUID(s, t.UUID)
+	return s
+}
@@ -0,0 +1,20 @@
+package pcb
+
+type TrackKind int
+
+const (
+	TrackSegment TrackKind = iota
+	TrackArc
+	TrackVia
+)
+
+func (k TrackKind) String() string {
+	switch k {
+	case TrackArc:
+		return "arc"
+	case TrackVia:
+		return "via"
+	default:
+		return "segment"
+	}
+}
@@ -0,0 +1,71 @@
+package pcb
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Zone is a copper or keepout area. Outline is its first (polygon ...);
+// filled polygons are left in the underlying tree untouched.
+type Zone struct {
+	Net      int
+	NetName  string
+	Layers   []string
+	Name     string
+	Priority int
+	Outline  []kicad.Point
+	UUID     string
+
+	node *sexpr.Sexpr
+}
+
+func newZone(s *sexpr.Sexpr) *Zone {
+	z := Zone{
+		Net:      node.ChildInt(s, "net", 0),
+		NetName:  node.ChildAtom(s, "net_name", 0),
+		Layers:   node.AtomStrings(node.Child(s, "layers")),
+		Name:     node.ChildAtom(s, "name", 0),
+		Priority: node.ChildInt(s, "priority", 0),
+		Outline:  node.Pts(node.Child(s, "polygon")),
+		UUID:     node.UUID(s),
+		node:     s,
+	}
+	if layer := node.Child(s, "layer"); layer != nil {
+		z.Layers = []string{node.Atom(layer, 0)}
+	}
+	return &z
+}
+
+func (z *Zone) Node() *sexpr.Sexpr {
+	return z.node
+}
+
+func (z *Zone) sync() *sexpr.Sexpr {
+	if z.node == nil {
+		z.node = node.New("zone")
+	}
+	s := z.node
+	node.SetChild(s, "net", node.Int(z.Net))
+	node.SetChild(s, "net_name", node.Str(z.NetName))
+	if len(z.Layers) == 1 && node.Child(s, "layers") == nil {
+		node.SetChild(s, "layer", node.Str(z.Layers[0]))
+	} else {
+		node.RemoveChildren(s, "layer")
+		layers := []*sexpr.SexprString{}
+		for _, l := range z.Layers {
+			layers = append(layers, node.Str(l))
+		}
+		node.SetChild(s, "layers", layers...)
+	}
+	node.SetUUID(s, z.UUID)
+	node.SetChildIf(s, z.Name != "", "name", node.Str(z.Name))
+	node.SetChildIf(s, z.Priority != 0, "priority", node.Int(z.Priority))
+	polygon := node.Child(s, "polygon")
+	if polygon == nil {
+		polygon = node.New("polygon")
+		node.Append(s, polygon)
+	}
+	node.SetPts(polygon, z.Outline)
+	return s
+}
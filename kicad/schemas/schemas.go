@@ -0,0 +1,110 @@
+// Package schemas bundles schemas for the KiCad file formats, per KiCad
+// major version, for use with the schema package.
+//
+// The schemas follow what each KiCad release writes. Subtrees that change
+// often between releases without affecting loading, such as (setup ...),
+// zones and dimensions, are accepted as opaque. The angle of (at X Y ANGLE)
+// accepts any atom, since KiCad may write "unlocked" in its place.
+package schemas
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/format"
+	"github.com/mlilley/go-sexpr/schema"
+)
+
+//go:embed files/*.schema
+var files embed.FS
+
+var (
+	mu     sync.Mutex
+	loaded = map[string]*schema.Schema{}
+)
+
+// versionless lists the roots whose format does not vary by KiCad version.
+var versionless = map[string]bool{
+	"fp_lib_table":  true,
+	"sym_lib_table": true,
+}
+
+// Get returns the schema for files with the given root written by KiCad
+// major version major. Library tables have a single schema and ignore
+// major.
+func Get(root string, major int) (*schema.Schema, error) {
+	name := root
+	if !versionless[root] {
+		name = root + "_" + strconv.Itoa(major)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if s, ok := loaded[name]; ok {
+		return s, nil
+	}
+	f, err := files.Open("files/" + name + ".schema")
+	if err != nil {
+		return nil, fmt.Errorf("no schema for '%s' version %d", root, major)
+	}
+	defer f.Close()
+	s, err := schema.Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	loaded[name] = s
+	return s, nil
+}
+
+// Majors returns the KiCad major versions there are schemas for root, in
+// ascending order. It is empty for library tables and unknown roots.
+func Majors(root string) []int {
+	majors := []int{}
+	entries, _ := files.ReadDir("files")
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".schema")
+		idx := strings.LastIndex(name, "_")
+		if idx == -1 || name[:idx] != root {
+			continue
+		}
+		if m, err := strconv.Atoi(name[idx+1:]); err == nil {
+			majors = append(majors, m)
+		}
+	}
+	sort.Ints(majors)
+	return majors
+}
+
+// For returns the schema matching the root and detected version of a
+// parsed KiCad file.
+func For(root *sexpr.Sexpr) (*schema.Schema, error) {
+	if root == nil {
+		return nil, errors.New("empty document")
+	}
+	if versionless[root.Name()] {
+		return Get(root.Name(), 0)
+	}
+	info := format.Detect(root)
+	if info.Kind == format.FileUnknown {
+		return nil, fmt.Errorf("unknown file kind '%s'", root.Name())
+	}
+	if info.Major == 0 {
+		return nil, fmt.Errorf("cannot detect KiCad version of %s", info.Kind)
+	}
+	return Get(root.Name(), info.Major)
+}
+
+// Validate checks a parsed KiCad file against the schema for its detected
+// version. See schema.Validate.
+func Validate(root *sexpr.Sexpr) error {
+	s, err := For(root)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(root, s)
+}
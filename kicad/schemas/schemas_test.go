@@ -0,0 +1,126 @@
+package schemas
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFile(t *testing.T, path string) *sexpr.Sexpr {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	root, err := sexpr.Parse(bufio.NewReader(f))
+	require.NoError(t, err)
+	return root
+}
+
+func parse(t *testing.T, input string) *sexpr.Sexpr {
+	root, err := sexpr.Parse(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	return root
+}
+
+func TestFixtures(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*/*")
+	require.NoError(t, err)
+	require.Equal(t, 16, len(paths))
+	for _, path := range paths {
+		root := parseFile(t, path)
+		require.NoError(t, Validate(root), path)
+	}
+}
+
+func TestSchemasLoad(t *testing.T) {
+	entries, err := files.ReadDir("files")
+	require.NoError(t, err)
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".schema")
+		root := name
+		major := 0
+		if idx := strings.LastIndex(name, "_"); !versionless[name] && idx != -1 {
+			root = name[:idx]
+			major = int(name[idx+1] - '0')
+		}
+		s, err := Get(root, major)
+		require.NoError(t, err, name)
+		require.Equal(t, name, s.Name)
+	}
+}
+
+func TestMajors(t *testing.T) {
+	require.Equal(t, []int{5, 6, 7, 8}, Majors("kicad_pcb"))
+	require.Equal(t, []int{6, 7, 8}, Majors("kicad_symbol_lib"))
+	require.Equal(t, []int{5}, Majors("module"))
+	require.Equal(t, []int{}, Majors("fp_lib_table"))
+	require.Equal(t, []int{}, Majors("nope"))
+}
+
+func TestWrongVersion(t *testing.T) {
+	// a KiCad 8 board uses uuid where KiCad 7 expects tstamp
+	root := parseFile(t, "testdata/8/board.kicad_pcb")
+	s, err := Get("kicad_pcb", 7)
+	require.NoError(t, err)
+	err = schema.Validate(root, s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected child 'uuid' in 'footprint'")
+	require.Contains(t, err.Error(), "unexpected child 'generator_version' in 'kicad_pcb'")
+
+	// and KiCad 7 writes bare hide flags KiCad 8 no longer reads
+	root = parseFile(t, "testdata/7/lib.kicad_sym")
+	s, err = Get("kicad_symbol_lib", 8)
+	require.NoError(t, err)
+	err = schema.Validate(root, s)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "too many params in 'pin_numbers'")
+}
+
+func TestInvalid(t *testing.T) {
+	err := Validate(parse(t, `(kicad_sch (version 20231120) (generator "eeschema")
+  (paper "A4")
+  (lib_symbols)
+  (wire (pts (xy 0 0) (xy 1 x)))
+  (symbol (lib_id "Device:R") (at 1 2 0) (uuid "u")))`))
+	require.Error(t, err)
+	verr, ok := err.(*schema.ValidationError)
+	require.True(t, ok)
+	messages := []string{}
+	for _, v := range verr.Violations {
+		messages = append(messages, v.Message)
+	}
+	require.Equal(t, []string{
+		"param 1 (y) of 'xy': 'x' is not a float",
+		"missing required child 'in_bom'",
+		"missing required child 'on_board'",
+		"missing required child 'uuid'",
+	}, messages)
+}
+
+func TestFor(t *testing.T) {
+	s, err := For(parse(t, `(kicad_pcb (version 20221018) (generator pcbnew))`))
+	require.NoError(t, err)
+	require.Equal(t, "kicad_pcb_7", s.Name)
+
+	s, err = For(parse(t, `(kicad_pcb (version 20171130) (host pcbnew "(5.1.9)-1"))`))
+	require.NoError(t, err)
+	require.Equal(t, "kicad_pcb_5", s.Name)
+
+	s, err = For(parse(t, `(sym_lib_table (version 7))`))
+	require.NoError(t, err)
+	require.Equal(t, "sym_lib_table", s.Name)
+
+	_, err = For(parse(t, `(kicad_pcb (version 20250101))`))
+	require.ErrorContains(t, err, "no schema for 'kicad_pcb' version 9")
+
+	_, err = For(parse(t, `(kicad_sch (generator eeschema))`))
+	require.ErrorContains(t, err, "cannot detect KiCad version of schematic")
+
+	_, err = For(parse(t, `(other)`))
+	require.ErrorContains(t, err, "unknown file kind 'other'")
+}
@@ -0,0 +1,56 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Label is a net label. Shape (input, output, bidirectional, tri_state or
+// passive) applies to global and hierarchical labels only.
+type Label struct {
+	Kind  LabelKind
+	Text  string
+	Shape string
+	At    kicad.Position
+	UUID  string
+
+	node *sexpr.Sexpr
+}
+
+func newLabel(s *sexpr.Sexpr) *Label {
+	l := Label{
+		Text:  node.Atom(s, 0),
+		Shape: node.ChildAtom(s, "shape", 0),
+		At:    node.ChildPosition(s, "at"),
+		UUID:  node.UUID(s),
+		node:  s,
+	}
+	switch s.Name() {
+	case "global_label":
+		l.Kind = LabelGlobal
+	case "hierarchical_label":
+		l.Kind = LabelHierarchical
+	}
+	return &l
+}
+
+func (l *Label) Node() *sexpr.Sexpr {
+	return l.node
+}
+
+func (l *Label) sync() *sexpr.Sexpr {
+	if l.node == nil {
+		l.node = node.New(l.Kind.String(),
+			node.New("effects", node.New("font", node.New("size", node.Float(1.27), node.Float(1.27)))),
+		)
+	} else if l.node.Name() != l.Kind.String() {
+		l.node.SetName(l.Kind.String())
+	}
+	s := l.node
+	node.SetAtom(s, 0, node.Str(l.Text))
+	node.SetChildIf(s, l.Kind != LabelLocal && l.Shape != "", "shape", node.Sym(l.Shape))
+	node.SetChildPosition(s, "at", l.At)
+	node.SetUUID(s, l.UUID)
+	return s
+}
@@ -0,0 +1,20 @@
+package schematic
+
+type LabelKind int
+
+const (
+	LabelLocal LabelKind = iota
+	LabelGlobal
+	LabelHierarchical
+)
+
+func (k LabelKind) String() string {
+	switch k {
+	case LabelGlobal:
+		return "global_label"
+	case LabelHierarchical:
+		return "hierarchical_label"
+	default:
+		return "label"
+	}
+}
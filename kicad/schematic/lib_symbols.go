@@ -0,0 +1,72 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// LibSymbols is the schematic's embedded cache of library symbols. Symbol
+// bodies are kept as sexprs; only names and properties are typed.
+type LibSymbols struct {
+	Symbols []*LibSymbol
+
+	node *sexpr.Sexpr
+}
+
+type LibSymbol struct {
+	Name       string
+	Properties []*Property
+
+	node *sexpr.Sexpr
+}
+
+func newLibSymbols(s *sexpr.Sexpr) *LibSymbols {
+	ls := LibSymbols{Symbols: []*LibSymbol{}, node: s}
+	for _, child := range node.Children(s, "symbol") {
+		ls.Symbols = append(ls.Symbols, &LibSymbol{
+			Name:       node.Atom(child, 0),
+			Properties: readProperties(child),
+			node:       child,
+		})
+	}
+	return &ls
+}
+
+func (ls *LibSymbols) Node() *sexpr.Sexpr {
+	return ls.node
+}
+
+func (ls *LibSymbols) Symbol(name string) *LibSymbol {
+	for _, s := range ls.Symbols {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (ls *LibSymbols) sync() *sexpr.Sexpr {
+	if ls.node == nil {
+		ls.node = node.New("lib_symbols")
+	}
+	nodes := []*sexpr.Sexpr{}
+	for _, s := range ls.Symbols {
+		if s.node == nil {
+			s.node = node.New("symbol")
+		}
+		node.SetAtom(s.node, 0, node.Str(s.Name))
+		syncProperties(s.node, s.Properties, kicad.Position{}, true)
+		nodes = append(nodes, s.node)
+	}
+	node.Sync(ls.node, []string{"symbol"}, nodes)
+	return ls.node
+}
+
+func (s *LibSymbol) Node() *sexpr.Sexpr {
+	return s.node
+}
+
+func (s *LibSymbol) Property(name string) (string, bool) {
+	return property(s.Properties, name)
+}
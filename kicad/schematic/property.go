@@ -0,0 +1,68 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Property struct {
+	Name  string
+	Value string
+
+	node *sexpr.Sexpr
+}
+
+func (p *Property) Node() *sexpr.Sexpr {
+	return p.node
+}
+
+func readProperties(s *sexpr.Sexpr) []*Property {
+	properties := []*Property{}
+	for _, child := range node.Children(s, "property") {
+		properties = append(properties, &Property{
+			Name:  node.Atom(child, 0),
+			Value: node.Atom(child, 1),
+			node:  child,
+		})
+	}
+	return properties
+}
+
+// syncProperties writes properties into s. New properties are placed at at
+// and optionally hidden.
+func syncProperties(s *sexpr.Sexpr, properties []*Property, at kicad.Position, hide bool) {
+	nodes := []*sexpr.Sexpr{}
+	for _, p := range properties {
+		if p.node == nil {
+			p.node = node.New("property", node.Str(p.Name), node.Str(p.Value))
+			node.SetChildPosition(p.node, "at", at)
+			effects := node.New("effects", node.New("font", node.New("size", node.Float(1.27), node.Float(1.27))))
+			node.SetFlag(effects, "hide", hide)
+			node.Append(p.node, effects)
+		}
+		node.SetAtom(p.node, 0, node.Str(p.Name))
+		node.SetAtom(p.node, 1, node.Str(p.Value))
+		nodes = append(nodes, p.node)
+	}
+	node.Sync(s, []string{"property"}, nodes)
+}
+
+func property(properties []*Property, name string) (string, bool) {
+	for _, p := range properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+func setProperty(properties []*Property, name string, value string) []*Property {
+	for _, p := range properties {
+		if p.Name == name {
+			p.Value = value
+			return properties
+		}
+	}
+	return append(properties, &Property{Name: name, Value: value})
+}
@@ -0,0 +1,163 @@
+// Package schematic maps KiCad schematic files (.kicad_sch) onto typed
+// structures.
+//
+// A Schematic holds one sheet: its placed symbols, wires, buses, junctions,
+// labels and hierarchical sheets, plus the library symbols embedded in
+// (lib_symbols ...). Child sheets live in their own files and are loaded
+// separately, by the File of each Sheet.
+package schematic
+
+import (
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Schematic struct {
+	Version    int
+	Generator  string
+	UUID       string
+	Paper      string
+	LibSymbols *LibSymbols
+	Symbols    []*SymbolInstance
+	Wires      []*Wire
+	Buses      []*Bus
+	Junctions  []*Junction
+	Labels     []*Label
+	Sheets     []*Sheet
+
+	root *sexpr.Sexpr
+}
+
+func NewSchematic() *Schematic {
+	return &Schematic{
+		LibSymbols: &LibSymbols{Symbols: []*LibSymbol{}},
+		root:       node.New("kicad_sch"),
+	}
+}
+
+func Load(r io.Reader) (*Schematic, error) {
+	root, err := node.Read(r, "kicad_sch")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Schematic, error) {
+	root, err := node.ReadFile(path, "kicad_sch")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Schematic, error) {
+	if err := node.CheckRoot(root, "kicad_sch"); err != nil {
+		return nil, err
+	}
+	sch := Schematic{
+		Version:    node.ChildInt(root, "version", 0),
+		Generator:  node.ChildAtom(root, "generator", 0),
+		UUID:       node.UUID(root),
+		Paper:      node.ChildAtom(root, "paper", 0),
+		LibSymbols: newLibSymbols(node.Child(root, "lib_symbols")),
+		Symbols:    []*SymbolInstance{},
+		Wires:      []*Wire{},
+		Buses:      []*Bus{},
+		Junctions:  []*Junction{},
+		Labels:     []*Label{},
+		Sheets:     []*Sheet{},
+		root:       root,
+	}
+	for _, child := range node.ChildSexprs(root) {
+		switch child.Name() {
+		case "symbol":
+			sch.Symbols = append(sch.Symbols, newSymbolInstance(child))
+		case "wire":
+			sch.Wires = append(sch.Wires, newWire(child))
+		case "bus":
+			sch.Buses = append(sch.Buses, newBus(child))
+		case "junction":
+			sch.Junctions = append(sch.Junctions, newJunction(child))
+		case "label", "global_label", "hierarchical_label":
+			sch.Labels = append(sch.Labels, newLabel(child))
+		case "sheet":
+			sch.Sheets = append(sch.Sheets, newSheet(child))
+		}
+	}
+	return &sch, nil
+}
+
+// Sexpr writes the typed fields back into the underlying tree and returns
+// its root.
+func (sch *Schematic) Sexpr() *sexpr.Sexpr {
+	root := sch.root
+	node.SetChildIf(root, sch.Version != 0, "version", node.Int(sch.Version))
+	node.SetChildIf(root, sch.Generator != "", "generator", node.Str(sch.Generator))
+	node.SetUUID(root, sch.UUID)
+	node.SetChildIf(root, sch.Paper != "", "paper", node.Str(sch.Paper))
+
+	if sch.LibSymbols != nil {
+		libSymbols := sch.LibSymbols.sync()
+		if node.Child(root, "lib_symbols") == nil {
+			node.Append(root, libSymbols)
+		}
+	}
+
+	nodes := []*sexpr.Sexpr{}
+	for _, j := range sch.Junctions {
+		nodes = append(nodes, j.sync())
+	}
+	node.Sync(root, []string{"junction"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, w := range sch.Wires {
+		nodes = append(nodes, w.sync())
+	}
+	node.Sync(root, []string{"wire"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, b := range sch.Buses {
+		nodes = append(nodes, b.sync())
+	}
+	node.Sync(root, []string{"bus"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, l := range sch.Labels {
+		nodes = append(nodes, l.sync())
+	}
+	node.Sync(root, []string{"label", "global_label", "hierarchical_label"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, s := range sch.Symbols {
+		nodes = append(nodes, s.sync())
+	}
+	node.Sync(root, []string{"symbol"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, s := range sch.Sheets {
+		nodes = append(nodes, s.sync())
+	}
+	node.Sync(root, []string{"sheet"}, nodes)
+
+	return root
+}
+
+func (sch *Schematic) Save(w io.Writer) error {
+	return node.Write(w, sch.Sexpr())
+}
+
+func (sch *Schematic) SaveFile(path string) error {
+	return node.WriteFile(path, sch.Sexpr())
+}
+
+func (sch *Schematic) Symbol(reference string) *SymbolInstance {
+	for _, s := range sch.Symbols {
+		if s.Reference() == reference {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package schematic
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	sch, err := LoadFile("testdata/root.kicad_sch")
+	require.NoError(t, err)
+
+	require.Equal(t, 20231120, sch.Version)
+	require.Equal(t, "eeschema", sch.Generator)
+	require.Equal(t, "6f3c5a7e-1b2d-4c3e-8f9a-0b1c2d3e4f50", sch.UUID)
+	require.Equal(t, "A4", sch.Paper)
+
+	require.Equal(t, 1, len(sch.LibSymbols.Symbols))
+	v, ok := sch.LibSymbols.Symbol("Device:R").Property("Reference")
+	require.True(t, ok)
+	require.Equal(t, "R", v)
+
+	require.Equal(t, 1, len(sch.Junctions))
+	require.Equal(t, kicad.Point{X: 127, Y: 76.2}, sch.Junctions[0].At)
+	require.Equal(t, 1, len(sch.Wires))
+	require.Equal(t, []kicad.Point{{X: 114.3, Y: 76.2}, {X: 127, Y: 76.2}}, sch.Wires[0].Points)
+	require.Equal(t, 1, len(sch.Buses))
+
+	require.Equal(t, 3, len(sch.Labels))
+	require.Equal(t, LabelLocal, sch.Labels[0].Kind)
+	require.Equal(t, "SDA", sch.Labels[0].Text)
+	require.Equal(t, LabelGlobal, sch.Labels[1].Kind)
+	require.Equal(t, "input", sch.Labels[1].Shape)
+	require.Equal(t, 180.0, sch.Labels[1].At.Angle)
+	require.Equal(t, LabelHierarchical, sch.Labels[2].Kind)
+
+	require.Equal(t, 1, len(sch.Symbols))
+	r1 := sch.Symbol("R1")
+	require.NotNil(t, r1)
+	require.Equal(t, "Device:R", r1.LibID)
+	require.Equal(t, "10k", r1.Value())
+	require.Equal(t, "Resistor_SMD:R_0603_1608Metric", r1.Footprint())
+	require.Equal(t, 1, r1.Unit)
+	require.True(t, r1.InBOM)
+	require.False(t, r1.DNP)
+
+	require.Equal(t, 1, len(sch.Sheets))
+	require.Equal(t, "Regulator", sch.Sheets[0].Name)
+	require.Equal(t, "regulator.kicad_sch", sch.Sheets[0].File)
+	require.Equal(t, 1, len(sch.Sheets[0].Pins))
+	require.Equal(t, "input", sch.Sheets[0].Pins[0].Type)
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/root.kicad_sch")
+	require.NoError(t, err)
+	root, err := node.Read(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	sch, err := Load(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, sch.Save(&sb))
+	require.Equal(t, root.String()+"\n", sb.String())
+}
+
+func TestSaveModified(t *testing.T) {
+	sch, err := LoadFile("testdata/root.kicad_sch")
+	require.NoError(t, err)
+
+	r1 := sch.Symbol("R1")
+	r1.SetProperty("Reference", "R7")
+	r1.SetProperty("MPN", "RC0603FR-0710KL")
+	r1.DNP = true
+	sch.Labels[0].Kind = LabelGlobal
+	sch.Labels[0].Shape = "bidirectional"
+	sch.Wires = append(sch.Wires, &Wire{Points: []kicad.Point{{X: 1, Y: 2}, {X: 3, Y: 2}}, UUID: "new-wire"})
+	sch.Buses = nil
+	sch.Sheets[0].Name = "LDO"
+	sch.Sheets[0].Pins = append(sch.Sheets[0].Pins, &SheetPin{Name: "EN", Type: "output", At: kicad.Position{X: 1, Y: 1}})
+
+	var sb strings.Builder
+	require.NoError(t, sch.Save(&sb))
+	out := sb.String()
+
+	sch, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	r7 := sch.Symbol("R7")
+	require.NotNil(t, r7)
+	v, _ := r7.Property("MPN")
+	require.Equal(t, "RC0603FR-0710KL", v)
+	require.True(t, r7.DNP)
+	require.Equal(t, LabelGlobal, sch.Labels[0].Kind)
+	require.Equal(t, "bidirectional", sch.Labels[0].Shape)
+	require.Equal(t, 2, len(sch.Wires))
+	require.Equal(t, "new-wire", sch.Wires[1].UUID)
+	require.Equal(t, 0, len(sch.Buses))
+	require.Equal(t, "LDO", sch.Sheets[0].Name)
+	require.Equal(t, 2, len(sch.Sheets[0].Pins))
+
+	// unrecognised tokens are kept, instance references follow the property
+	require.Contains(t, out, `(reference "R7")`)
+	require.Contains(t, out, "(no_connect")
+	require.Contains(t, out, `(title "Power")`)
+	require.Contains(t, out, "(sheet_instances")
+	require.Contains(t, out, "(pin_numbers hide)")
+}
@@ -0,0 +1,117 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Sheet is a hierarchical sheet. Name and File are read from the
+// "Sheetname"/"Sheetfile" properties, or "Sheet name"/"Sheet file" in
+// KiCad 6 files.
+type Sheet struct {
+	At         kicad.Point
+	Size       kicad.Point
+	Name       string
+	File       string
+	UUID       string
+	Properties []*Property
+	Pins       []*SheetPin
+
+	node *sexpr.Sexpr
+	name *Property
+	file *Property
+}
+
+type SheetPin struct {
+	Name string
+	Type string
+	At   kicad.Position
+	UUID string
+
+	node *sexpr.Sexpr
+}
+
+func newSheet(s *sexpr.Sexpr) *Sheet {
+	sh := Sheet{
+		At:         node.ChildPoint(s, "at"),
+		Size:       node.ChildPoint(s, "size"),
+		UUID:       node.UUID(s),
+		Properties: []*Property{},
+		Pins:       []*SheetPin{},
+		node:       s,
+	}
+	for _, p := range readProperties(s) {
+		switch p.Name {
+		case "Sheetname", "Sheet name":
+			sh.name = p
+			sh.Name = p.Value
+		case "Sheetfile", "Sheet file":
+			sh.file = p
+			sh.File = p.Value
+		default:
+			sh.Properties = append(sh.Properties, p)
+		}
+	}
+	for _, child := range node.Children(s, "pin") {
+		sh.Pins = append(sh.Pins, &SheetPin{
+			Name: node.Atom(child, 0),
+			Type: node.Atom(child, 1),
+			At:   node.ChildPosition(child, "at"),
+			UUID: node.UUID(child),
+			node: child,
+		})
+	}
+	return &sh
+}
+
+func (sh *Sheet) Node() *sexpr.Sexpr {
+	return sh.node
+}
+
+func (sh *Sheet) Property(name string) (string, bool) {
+	return property(sh.Properties, name)
+}
+
+func (sh *Sheet) SetProperty(name string, value string) {
+	sh.Properties = setProperty(sh.Properties, name, value)
+}
+
+func (sh *Sheet) sync() *sexpr.Sexpr {
+	if sh.node == nil {
+		sh.node = node.New("sheet")
+	}
+	s := sh.node
+	node.SetChildPoint(s, "at", sh.At)
+	node.SetChildPoint(s, "size", sh.Size)
+	node.SetUUID(s, sh.UUID)
+
+	if sh.name == nil {
+		sh.name = &Property{Name: "Sheetname"}
+	}
+	if sh.file == nil {
+		sh.file = &Property{Name: "Sheetfile"}
+	}
+	sh.name.Value = sh.Name
+	sh.file.Value = sh.File
+	properties := append([]*Property{sh.name, sh.file}, sh.Properties...)
+	syncProperties(s, properties, kicad.Position{X: sh.At.X, Y: sh.At.Y}, false)
+
+	nodes := []*sexpr.Sexpr{}
+	for _, pin := range sh.Pins {
+		if pin.node == nil {
+			pin.node = node.New("pin")
+		}
+		node.SetAtom(pin.node, 0, node.Str(pin.Name))
+		node.SetAtom(pin.node, 1, node.Sym(pin.Type))
+		node.SetChildPosition(pin.node, "at", pin.At)
+		node.SetUUID(pin.node, pin.UUID)
+		nodes = append(nodes, pin.node)
+	}
+	node.Sync(s, []string{"pin"}, nodes)
+	return s
+}
+
+func (pin *SheetPin) Node() *sexpr.Sexpr {
+	return pin.node
+}
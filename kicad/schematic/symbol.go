@@ -0,0 +1,95 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// SymbolInstance is a symbol placed on the sheet. Its fields, including
+// Reference and Value, are Properties.
+type SymbolInstance struct {
+	LibID      string
+	At         kicad.Position
+	Mirror     string
+	Unit       int
+	InBOM      bool
+	OnBoard    bool
+	DNP        bool
+	UUID       string
+	Properties []*Property
+
+	node      *sexpr.Sexpr
+	reference string
+}
+
+func newSymbolInstance(s *sexpr.Sexpr) *SymbolInstance {
+	si := SymbolInstance{
+		LibID:      node.ChildAtom(s, "lib_id", 0),
+		At:         node.ChildPosition(s, "at"),
+		Mirror:     node.ChildAtom(s, "mirror", 0),
+		Unit:       node.ChildInt(s, "unit", 0),
+		InBOM:      node.Flag(s, "in_bom"),
+		OnBoard:    node.Flag(s, "on_board"),
+		DNP:        node.Flag(s, "dnp"),
+		UUID:       node.UUID(s),
+		Properties: readProperties(s),
+		node:       s,
+	}
+	si.reference = si.Reference()
+	return &si
+}
+
+func (si *SymbolInstance) Node() *sexpr.Sexpr {
+	return si.node
+}
+
+func (si *SymbolInstance) Property(name string) (string, bool) {
+	return property(si.Properties, name)
+}
+
+func (si *SymbolInstance) SetProperty(name string, value string) {
+	si.Properties = setProperty(si.Properties, name, value)
+}
+
+func (si *SymbolInstance) Reference() string {
+	v, _ := si.Property("Reference")
+	return v
+}
+
+func (si *SymbolInstance) Value() string {
+	v, _ := si.Property("Value")
+	return v
+}
+
+func (si *SymbolInstance) Footprint() string {
+	v, _ := si.Property("Footprint")
+	return v
+}
+
+func (si *SymbolInstance) sync() *sexpr.Sexpr {
+	if si.node == nil {
+		si.node = node.New("symbol")
+	}
+	s := si.node
+	node.SetChild(s, "lib_id", node.Str(si.LibID))
+	node.SetChildPosition(s, "at", si.At)
+	node.SetChildIf(s, si.Mirror != "", "mirror", node.Sym(si.Mirror))
+	node.SetChildIf(s, si.Unit != 0, "unit", node.Int(si.Unit))
+	node.SetFlag(s, "in_bom", si.InBOM)
+	node.SetFlag(s, "on_board", si.OnBoard)
+	node.SetFlag(s, "dnp", si.DNP)
+	node.SetUUID(s, si.UUID)
+	syncProperties(s, si.Properties, si.At, true)
+
+	// the per-project instance data repeats the reference; keep it in step
+	if ref := si.Reference(); ref != si.reference {
+		for _, r := range s.FindChildrenByNameExact("reference", -1) {
+			if node.Atom(r, 0) == si.reference {
+				node.SetAtoms(r, node.Str(ref))
+			}
+		}
+		si.reference = ref
+	}
+	return s
+}
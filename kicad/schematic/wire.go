@@ -0,0 +1,93 @@
+package schematic
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Wire struct {
+	Points []kicad.Point
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+type Bus struct {
+	Points []kicad.Point
+	UUID   string
+
+	node *sexpr.Sexpr
+}
+
+type Junction struct {
+	At       kicad.Point
+	Diameter float64
+	UUID     string
+
+	node *sexpr.Sexpr
+}
+
+func newWire(s *sexpr.Sexpr) *Wire {
+	return &Wire{Points: node.Pts(s), UUID: node.UUID(s), node: s}
+}
+
+func (w *Wire) Node() *sexpr.Sexpr {
+	return w.node
+}
+
+func (w *Wire) sync() *sexpr.Sexpr {
+	if w.node == nil {
+		w.node = newLine("wire")
+	}
+	node.SetPts(w.node, w.Points)
+	node.SetUUID(w.node, w.UUID)
+	return w.node
+}
+
+func newBus(s *sexpr.Sexpr) *Bus {
+	return &Bus{Points: node.Pts(s), UUID: node.UUID(s), node: s}
+}
+
+func (b *Bus) Node() *sexpr.Sexpr {
+	return b.node
+}
+
+func (b *Bus) sync() *sexpr.Sexpr {
+	if b.node == nil {
+		b.node = newLine("bus")
+	}
+	node.SetPts(b.node, b.Points)
+	node.SetUUID(b.node, b.UUID)
+	return b.node
+}
+
+func newLine(name string) *sexpr.Sexpr {
+	return node.New(name,
+		node.New("pts"),
+		node.New("stroke", node.New("width", node.Int(0)), node.New("type", node.Sym("default"))),
+	)
+}
+
+func newJunction(s *sexpr.Sexpr) *Junction {
+	return &Junction{
+		At:       node.ChildPoint(s, "at"),
+		Diameter: node.ChildFloat(s, "diameter", 0),
+		UUID:     node.UUID(s),
+		node:     s,
+	}
+}
+
+func (j *Junction) Node() *sexpr.Sexpr {
+	return j.node
+}
+
+func (j *Junction) sync() *sexpr.Sexpr {
+	if j.node == nil {
+		j.node = node.New("junction")
+	}
+	node.SetChildPoint(j.node, "at", j.At)
+	node.SetChild(j.node, "diameter", node.Float(j.Diameter))
+	node.SetUUID(j.node, j.UUID)
+	return j.node
+}
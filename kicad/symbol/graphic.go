@@ -0,0 +1,86 @@
+package symbol
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Graphic is a symbol body drawing. Kind is the token name and selects which
+// fields apply: Start and End for rectangles, Start, Mid and End for arcs,
+// Center and Radius for circles, Points for polylines and beziers, and Text
+// and At for text.
+type Graphic struct {
+	Kind   string
+	Start  kicad.Point
+	Mid    kicad.Point
+	End    kicad.Point
+	Center kicad.Point
+	Radius float64
+	Points []kicad.Point
+	Text   string
+	At     kicad.Position
+	Width  float64
+	Fill   string
+
+	node *sexpr.Sexpr
+}
+
+func newGraphic(s *sexpr.Sexpr) *Graphic {
+	g := Graphic{
+		Kind:   s.Name(),
+		Start:  node.ChildPoint(s, "start"),
+		Mid:    node.ChildPoint(s, "mid"),
+		End:    node.ChildPoint(s, "end"),
+		Center: node.ChildPoint(s, "center"),
+		Radius: node.ChildFloat(s, "radius", 0),
+		Points: node.Pts(s),
+		At:     node.ChildPosition(s, "at"),
+		Width:  node.ChildFloat(node.Child(s, "stroke"), "width", 0),
+		Fill:   node.ChildAtom(node.Child(s, "fill"), "type", 0),
+		node:   s,
+	}
+	if g.Kind == "text" {
+		g.Text = node.Atom(s, 0)
+	}
+	return &g
+}
+
+func (g *Graphic) Node() *sexpr.Sexpr {
+	return g.node
+}
+
+func (g *Graphic) sync() *sexpr.Sexpr {
+	if g.node == nil || g.node.Name() != g.Kind {
+		g.node = node.New(g.Kind)
+		if g.Kind != "text" {
+			node.Append(g.node, node.New("stroke", node.New("width", node.Int(0)), node.New("type", node.Sym("default"))))
+			node.Append(g.node, node.New("fill", node.New("type", node.Sym("none"))))
+		}
+	}
+	s := g.node
+	switch g.Kind {
+	case "rectangle":
+		node.SetChildPoint(s, "start", g.Start)
+		node.SetChildPoint(s, "end", g.End)
+	case "arc":
+		node.SetChildPoint(s, "start", g.Start)
+		node.SetChildPoint(s, "mid", g.Mid)
+		node.SetChildPoint(s, "end", g.End)
+	case "circle":
+		node.SetChildPoint(s, "center", g.Center)
+		node.SetChild(s, "radius", node.Float(g.Radius))
+	case "polyline", "bezier":
+		node.SetPts(s, g.Points)
+	case "text":
+		node.SetAtom(s, 0, node.Str(g.Text))
+		node.SetChildPosition(s, "at", g.At)
+	}
+	if stroke := node.Child(s, "stroke"); stroke != nil {
+		node.SetChild(stroke, "width", node.Float(g.Width))
+	}
+	if fill := node.Child(s, "fill"); fill != nil && g.Fill != "" {
+		node.SetChild(fill, "type", node.Sym(g.Fill))
+	}
+	return s
+}
@@ -0,0 +1,146 @@
+// Package symbol maps KiCad symbol libraries (.kicad_sym) onto typed
+// structures.
+//
+// A symbol is drawn in units, the nested (symbol "NAME_UNIT_STYLE" ...)
+// bodies, while a symbol that extends another carries only the properties it
+// overrides. Library.Resolve flattens such a symbol into a standalone copy.
+package symbol
+
+import (
+	"fmt"
+	"io"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+type Library struct {
+	Version   int
+	Generator string
+	Symbols   []*Symbol
+
+	root *sexpr.Sexpr
+}
+
+func NewLibrary() *Library {
+	return &Library{Symbols: []*Symbol{}, root: node.New("kicad_symbol_lib")}
+}
+
+func Load(r io.Reader) (*Library, error) {
+	root, err := node.Read(r, "kicad_symbol_lib")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Library, error) {
+	root, err := node.ReadFile(path, "kicad_symbol_lib")
+	if err != nil {
+		return nil, err
+	}
+	return FromSexpr(root)
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Library, error) {
+	if err := node.CheckRoot(root, "kicad_symbol_lib"); err != nil {
+		return nil, err
+	}
+	lib := Library{
+		Version:   node.ChildInt(root, "version", 0),
+		Generator: node.ChildAtom(root, "generator", 0),
+		Symbols:   []*Symbol{},
+		root:      root,
+	}
+	for _, child := range node.Children(root, "symbol") {
+		lib.Symbols = append(lib.Symbols, newSymbol(child))
+	}
+	return &lib, nil
+}
+
+// Sexpr writes the typed fields back into the underlying tree and returns
+// its root.
+func (lib *Library) Sexpr() *sexpr.Sexpr {
+	root := lib.root
+	node.SetChildIf(root, lib.Version != 0, "version", node.Int(lib.Version))
+	node.SetChildIf(root, lib.Generator != "", "generator", node.Str(lib.Generator))
+	nodes := []*sexpr.Sexpr{}
+	for _, s := range lib.Symbols {
+		nodes = append(nodes, s.sync())
+	}
+	node.Sync(root, []string{"symbol"}, nodes)
+	return root
+}
+
+func (lib *Library) Save(w io.Writer) error {
+	return node.Write(w, lib.Sexpr())
+}
+
+func (lib *Library) SaveFile(path string) error {
+	return node.WriteFile(path, lib.Sexpr())
+}
+
+func (lib *Library) Symbol(name string) *Symbol {
+	for _, s := range lib.Symbols {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Resolve returns the named symbol with its extends chain flattened: units
+// and properties are taken from the base symbol and overridden by the
+// properties of each derived symbol. The result is a copy that is not part
+// of the library.
+func (lib *Library) Resolve(name string) (*Symbol, error) {
+	sym := lib.Symbol(name)
+	if sym == nil {
+		return nil, fmt.Errorf("symbol '%s' not found", name)
+	}
+	chain := []*Symbol{sym}
+	seen := map[string]bool{name: true}
+	for sym.Extends != "" {
+		if seen[sym.Extends] {
+			return nil, fmt.Errorf("symbol '%s' extends itself through '%s'", name, sym.Extends)
+		}
+		parent := lib.Symbol(sym.Extends)
+		if parent == nil {
+			return nil, fmt.Errorf("symbol '%s' extends unknown symbol '%s'", sym.Name, sym.Extends)
+		}
+		seen[parent.Name] = true
+		chain = append(chain, parent)
+		sym = parent
+	}
+
+	// pending edits are applied to copies, not to the library's symbols
+	base := chain[len(chain)-1].detached().sync()
+	for i := len(chain) - 2; i >= 0; i-- {
+		derived := chain[i].detached().sync()
+		for _, p := range node.Children(derived, "property") {
+			replaceProperty(base, node.Clone(p))
+		}
+	}
+	node.SetAtom(base, 0, node.Str(name))
+	node.RemoveChildren(base, "extends")
+
+	// syncing renames the units after the derived symbol
+	resolved := newSymbol(base)
+	resolved.sync()
+	return resolved, nil
+}
+
+func replaceProperty(s *sexpr.Sexpr, p *sexpr.Sexpr) {
+	for _, old := range node.Children(s, "property") {
+		if node.Atom(old, 0) == node.Atom(p, 0) {
+			for i, param := range s.Params() {
+				if param.Value() == old {
+					s.SetParamSexpr(i, p)
+					return
+				}
+			}
+		}
+	}
+	nodes := append(node.Children(s, "property"), p)
+	node.Sync(s, []string{"property"}, nodes)
+}
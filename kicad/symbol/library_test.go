@@ -0,0 +1,160 @@
+package symbol
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	lib, err := LoadFile("testdata/lib.kicad_sym")
+	require.NoError(t, err)
+
+	require.Equal(t, 20231120, lib.Version)
+	require.Equal(t, 3, len(lib.Symbols))
+
+	r := lib.Symbol("R")
+	require.NotNil(t, r)
+	require.True(t, r.InBOM)
+	require.False(t, r.Power)
+	v, ok := r.Property("ki_keywords")
+	require.True(t, ok)
+	require.Equal(t, "R res resistor", v)
+	require.Equal(t, 2, len(r.Units))
+	require.Equal(t, 0, r.Units[0].Number)
+	require.Equal(t, 1, r.Units[0].Style)
+	require.Equal(t, "rectangle", r.Units[0].Graphics[0].Kind)
+	require.Equal(t, 0.254, r.Units[0].Graphics[0].Width)
+	require.Equal(t, "none", r.Units[0].Graphics[0].Fill)
+	unit := r.Unit(1, 1)
+	require.NotNil(t, unit)
+	require.Equal(t, 2, len(unit.Pins))
+	require.Equal(t, "2", unit.Pins[1].Number)
+	require.Equal(t, "passive", unit.Pins[1].Type)
+	require.Equal(t, kicad.Position{X: 0, Y: -3.81, Angle: 90}, unit.Pins[1].At)
+
+	us := lib.Symbol("R_US")
+	require.Equal(t, "R", us.Extends)
+	require.Equal(t, 0, len(us.Units))
+
+	gnd := lib.Symbol("GND")
+	require.True(t, gnd.Power)
+	require.True(t, gnd.Pins()[0].Hidden)
+	require.Equal(t, 6, len(gnd.Units[0].Graphics[0].Points))
+}
+
+func TestResolve(t *testing.T) {
+	lib, err := LoadFile("testdata/lib.kicad_sym")
+	require.NoError(t, err)
+
+	sym, err := lib.Resolve("R_US")
+	require.NoError(t, err)
+	require.Equal(t, "R_US", sym.Name)
+	require.Equal(t, "", sym.Extends)
+	require.Equal(t, 2, len(sym.Units))
+	require.Equal(t, "R_US_1_1", sym.Units[1].Node().Params()[0].Value().(*sexpr.SexprString).Value())
+	require.Equal(t, 2, len(sym.Pins()))
+	v, _ := sym.Property("Value")
+	require.Equal(t, "R_US", v)
+	v, _ = sym.Property("ki_keywords")
+	require.Equal(t, "R res resistor US", v)
+	v, ok := sym.Property("Footprint")
+	require.True(t, ok)
+	require.Equal(t, "", v)
+
+	// the library itself is untouched
+	require.Equal(t, 0, len(lib.Symbol("R_US").Units))
+	v, _ = lib.Symbol("R").Property("Value")
+	require.Equal(t, "R", v)
+
+	_, err = lib.Resolve("nope")
+	require.ErrorContains(t, err, "not found")
+
+	// pending edits are resolved without being written into the library
+	before := lib.Sexpr().String()
+	lib.Symbol("R").SetProperty("Value", "R_edited")
+	lib.Symbol("R").Units[1].Pins[0].Hidden = true
+	lib.Symbol("R_US").SetProperty("Manufacturer", "ACME")
+	r := lib.Symbol("R").Node()
+	us := lib.Symbol("R_US").Node()
+	rText, usText := r.String(), us.String()
+	sym, err = lib.Resolve("R_US")
+	require.NoError(t, err)
+	v, _ = sym.Property("Manufacturer")
+	require.Equal(t, "ACME", v)
+	require.True(t, sym.Units[1].Pins[0].Hidden)
+	require.Equal(t, rText, r.String())
+	require.Equal(t, usText, us.String())
+	require.Nil(t, lib.Symbol("R_US").Properties[len(lib.Symbol("R_US").Properties)-1].Node())
+	require.NotEqual(t, before, lib.Sexpr().String())
+
+	lib.Symbol("R").Extends = "R_US"
+	_, err = lib.Resolve("R_US")
+	require.ErrorContains(t, err, "extends itself")
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	data, err := os.ReadFile("testdata/lib.kicad_sym")
+	require.NoError(t, err)
+	root, err := node.Read(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	lib, err := Load(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	var sb strings.Builder
+	require.NoError(t, lib.Save(&sb))
+	require.Equal(t, root.String()+"\n", sb.String())
+	require.Equal(t, string(data), sb.String())
+}
+
+func TestSaveModified(t *testing.T) {
+	lib, err := LoadFile("testdata/lib.kicad_sym")
+	require.NoError(t, err)
+
+	for _, sym := range lib.Symbols {
+		sym.SetProperty("Manufacturer", "ACME")
+	}
+	r := lib.Symbol("R")
+	r.Name = "R_Generic"
+	r.RemoveProperty("ki_keywords")
+	r.Unit(1, 1).Pins[0].Hidden = true
+	lib.Symbol("R_US").Extends = "R_Generic"
+	lib.Symbols = append(lib.Symbols, &Symbol{
+		Name:    "TP",
+		InBOM:   true,
+		OnBoard: true,
+		Properties: []*Property{
+			{Name: "Reference", Value: "TP"},
+		},
+		Units: []*Unit{{Number: 1, Style: 1, Pins: []*Pin{{Type: "passive", Shape: "line", Length: 2.54, Name: "~", Number: "1"}}}},
+	})
+
+	var sb strings.Builder
+	require.NoError(t, lib.Save(&sb))
+	out := sb.String()
+
+	lib, err = Load(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Equal(t, 4, len(lib.Symbols))
+	r = lib.Symbol("R_Generic")
+	require.NotNil(t, r)
+	require.NotNil(t, r.Unit(1, 1))
+	require.True(t, r.Unit(1, 1).Pins[0].Hidden)
+	_, ok := r.Property("ki_keywords")
+	require.False(t, ok)
+	v, _ := lib.Symbol("GND").Property("Manufacturer")
+	require.Equal(t, "ACME", v)
+	sym, err := lib.Resolve("R_US")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(sym.Pins()))
+	tp := lib.Symbol("TP")
+	require.Equal(t, "1", tp.Unit(1, 1).Pins[0].Number)
+
+	require.Contains(t, out, `(symbol "R_Generic_0_1"`)
+	require.Contains(t, out, "(pin_numbers hide)")
+}
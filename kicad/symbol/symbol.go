@@ -0,0 +1,205 @@
+package symbol
+
+import (
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Symbol is a library symbol. A symbol that Extends another carries only its
+// own properties; use Library.Resolve to get the inherited units.
+type Symbol struct {
+	Name       string
+	Extends    string
+	Power      bool
+	InBOM      bool
+	OnBoard    bool
+	Properties []*Property
+	Units      []*Unit
+
+	node *sexpr.Sexpr
+}
+
+type Property struct {
+	Name  string
+	Value string
+	At    kicad.Position
+
+	node *sexpr.Sexpr
+}
+
+func newSymbol(s *sexpr.Sexpr) *Symbol {
+	sym := Symbol{
+		Name:       node.Atom(s, 0),
+		Extends:    node.ChildAtom(s, "extends", 0),
+		Power:      node.Child(s, "power") != nil,
+		InBOM:      node.Flag(s, "in_bom"),
+		OnBoard:    node.Flag(s, "on_board"),
+		Properties: []*Property{},
+		Units:      []*Unit{},
+		node:       s,
+	}
+	for _, child := range node.Children(s, "property") {
+		sym.Properties = append(sym.Properties, &Property{
+			Name:  node.Atom(child, 0),
+			Value: node.Atom(child, 1),
+			At:    node.ChildPosition(child, "at"),
+			node:  child,
+		})
+	}
+	for _, child := range node.Children(s, "symbol") {
+		sym.Units = append(sym.Units, newUnit(child))
+	}
+	return &sym
+}
+
+func (sym *Symbol) Node() *sexpr.Sexpr {
+	return sym.node
+}
+
+func (sym *Symbol) Property(name string) (string, bool) {
+	for _, p := range sym.Properties {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+func (sym *Symbol) SetProperty(name string, value string) {
+	for _, p := range sym.Properties {
+		if p.Name == name {
+			p.Value = value
+			return
+		}
+	}
+	sym.Properties = append(sym.Properties, &Property{Name: name, Value: value})
+}
+
+func (sym *Symbol) RemoveProperty(name string) {
+	properties := []*Property{}
+	for _, p := range sym.Properties {
+		if p.Name != name {
+			properties = append(properties, p)
+		}
+	}
+	sym.Properties = properties
+}
+
+func (sym *Symbol) Unit(number int, style int) *Unit {
+	for _, u := range sym.Units {
+		if u.Number == number && u.Style == style {
+			return u
+		}
+	}
+	return nil
+}
+
+// Pins returns the pins of all units.
+func (sym *Symbol) Pins() []*Pin {
+	pins := []*Pin{}
+	for _, u := range sym.Units {
+		pins = append(pins, u.Pins...)
+	}
+	return pins
+}
+
+func (sym *Symbol) sync() *sexpr.Sexpr {
+	if sym.node == nil {
+		sym.node = node.New("symbol")
+	}
+	s := sym.node
+	node.SetAtom(s, 0, node.Str(sym.Name))
+	node.SetChildIf(s, sym.Extends != "", "extends", node.Str(sym.Extends))
+	if sym.Power && node.Child(s, "power") == nil {
+		node.Append(s, node.New("power"))
+	} else if !sym.Power {
+		node.RemoveChildren(s, "power")
+	}
+	if sym.Extends == "" || node.Child(s, "in_bom") != nil {
+		node.SetChild(s, "in_bom", node.Sym(yesNo(sym.InBOM)))
+		node.SetChild(s, "on_board", node.Sym(yesNo(sym.OnBoard)))
+	}
+
+	nodes := []*sexpr.Sexpr{}
+	for _, p := range sym.Properties {
+		if p.node == nil {
+			p.node = node.New("property", node.Str(p.Name), node.Str(p.Value))
+			node.SetChildPosition(p.node, "at", p.At)
+			effects := node.New("effects", node.New("font", node.New("size", node.Float(1.27), node.Float(1.27))))
+			node.SetFlag(effects, "hide", true)
+			node.Append(p.node, effects)
+		}
+		node.SetAtom(p.node, 0, node.Str(p.Name))
+		node.SetAtom(p.node, 1, node.Str(p.Value))
+		if node.Child(p.node, "at") != nil || p.At != (kicad.Position{}) {
+			node.SetChildPosition(p.node, "at", p.At)
+		}
+		nodes = append(nodes, p.node)
+	}
+	node.Sync(s, []string{"property"}, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, u := range sym.Units {
+		nodes = append(nodes, u.sync(sym.Name))
+	}
+	node.Sync(s, []string{"symbol"}, nodes)
+	return s
+}
+
+// detached returns a copy of sym whose values refer to a clone of its tree,
+// so syncing the copy leaves sym, and the library holding it, as they are.
+func (sym *Symbol) detached() *Symbol {
+	clones := map[*sexpr.Sexpr]*sexpr.Sexpr{}
+	c := *sym
+	if sym.node != nil {
+		c.node = node.Clone(sym.node)
+		mapClones(sym.node, c.node, clones)
+	}
+	c.Properties = []*Property{}
+	for _, p := range sym.Properties {
+		pc := *p
+		pc.node = clones[p.node]
+		c.Properties = append(c.Properties, &pc)
+	}
+	c.Units = []*Unit{}
+	for _, u := range sym.Units {
+		uc := *u
+		uc.node = clones[u.node]
+		uc.Pins = []*Pin{}
+		for _, p := range u.Pins {
+			pc := *p
+			pc.node = clones[p.node]
+			uc.Pins = append(uc.Pins, &pc)
+		}
+		uc.Graphics = []*Graphic{}
+		for _, g := range u.Graphics {
+			gc := *g
+			gc.node = clones[g.node]
+			uc.Graphics = append(uc.Graphics, &gc)
+		}
+		c.Units = append(c.Units, &uc)
+	}
+	return &c
+}
+
+// mapClones records the sexpr in clone matching each sexpr in orig.
+func mapClones(orig *sexpr.Sexpr, clone *sexpr.Sexpr, clones map[*sexpr.Sexpr]*sexpr.Sexpr) {
+	clones[orig] = clone
+	for i, param := range orig.Params() {
+		if child, ok := param.Value().(*sexpr.Sexpr); ok {
+			mapClones(child, clone.Params()[i].Value().(*sexpr.Sexpr), clones)
+		}
+	}
+}
+
+func (p *Property) Node() *sexpr.Sexpr {
+	return p.node
+}
+
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,118 @@
+package symbol
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/mlilley/go-sexpr/kicad"
+	"github.com/mlilley/go-sexpr/kicad/internal/node"
+)
+
+// Unit is one of a symbol's nested (symbol "NAME_UNIT_STYLE" ...) bodies.
+// Unit 0 holds drawing common to all units; Style 1 is the normal body and
+// Style 2 the De Morgan alternate.
+type Unit struct {
+	Number   int
+	Style    int
+	Pins     []*Pin
+	Graphics []*Graphic
+
+	node *sexpr.Sexpr
+}
+
+type Pin struct {
+	Type   string
+	Shape  string
+	At     kicad.Position
+	Length float64
+	Name   string
+	Number string
+	Hidden bool
+
+	node *sexpr.Sexpr
+}
+
+var graphicNames = []string{"arc", "circle", "bezier", "polyline", "rectangle", "text"}
+
+func newUnit(s *sexpr.Sexpr) *Unit {
+	u := Unit{Pins: []*Pin{}, Graphics: []*Graphic{}, node: s}
+	name := node.Atom(s, 0)
+	if i := strings.LastIndex(name, "_"); i > 0 {
+		u.Style, _ = strconv.Atoi(name[i+1:])
+		name = name[:i]
+		if i := strings.LastIndex(name, "_"); i > 0 {
+			u.Number, _ = strconv.Atoi(name[i+1:])
+		}
+	}
+	for _, child := range node.ChildSexprs(s) {
+		switch child.Name() {
+		case "pin":
+			u.Pins = append(u.Pins, &Pin{
+				Type:   node.Atom(child, 0),
+				Shape:  node.Atom(child, 1),
+				At:     node.ChildPosition(child, "at"),
+				Length: node.ChildFloat(child, "length", 0),
+				Name:   node.ChildAtom(child, "name", 0),
+				Number: node.ChildAtom(child, "number", 0),
+				Hidden: node.Flag(child, "hide"),
+				node:   child,
+			})
+		case "arc", "circle", "bezier", "polyline", "rectangle", "text":
+			u.Graphics = append(u.Graphics, newGraphic(child))
+		}
+	}
+	return &u
+}
+
+func (u *Unit) Node() *sexpr.Sexpr {
+	return u.node
+}
+
+func (u *Unit) name(symbol string) string {
+	return fmt.Sprintf("%s_%d_%d", symbol, u.Number, u.Style)
+}
+
+func (u *Unit) sync(symbol string) *sexpr.Sexpr {
+	if u.node == nil {
+		u.node = node.New("symbol")
+	}
+	s := u.node
+	node.SetAtom(s, 0, node.Str(u.name(symbol)))
+
+	nodes := []*sexpr.Sexpr{}
+	for _, g := range u.Graphics {
+		nodes = append(nodes, g.sync())
+	}
+	node.Sync(s, graphicNames, nodes)
+
+	nodes = []*sexpr.Sexpr{}
+	for _, p := range u.Pins {
+		nodes = append(nodes, p.sync())
+	}
+	node.Sync(s, []string{"pin"}, nodes)
+	return s
+}
+
+func (p *Pin) Node() *sexpr.Sexpr {
+	return p.node
+}
+
+func (p *Pin) sync() *sexpr.Sexpr {
+	if p.node == nil {
+		font := func() *sexpr.Sexpr {
+			return node.New("effects", node.New("font", node.New("size", node.Float(1.27), node.Float(1.27))))
+		}
+		p.node = node.New("pin", node.New("name", node.Str(""), font()), node.New("number", node.Str(""), font()))
+	}
+	s := p.node
+	node.SetAtom(s, 0, node.Sym(p.Type))
+	node.SetAtom(s, 1, node.Sym(p.Shape))
+	node.SetChildPosition(s, "at", p.At)
+	node.SetChild(s, "length", node.Float(p.Length))
+	node.SetFlag(s, "hide", p.Hidden)
+	node.SetAtom(node.Child(s, "name"), 0, node.Str(p.Name))
+	node.SetAtom(node.Child(s, "number"), 0, node.Str(p.Number))
+	return s
+}
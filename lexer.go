@@ -17,6 +17,9 @@ type Lexer struct {
 	startLine   int
 	startColumn int
 	content     string
+	quote       rune
+	escapes     bool
+	maxToken    int
 	input       *bufio.Reader
 }
 
@@ -29,10 +32,43 @@ func NewLexer(input *bufio.Reader) *Lexer {
 		startLine:   1,
 		startColumn: 1,
 		content:     "",
+		quote:       '"',
 		input:       input,
 	}
 }
 
+// SetQuote sets the rune that delimits quoted strings, '"' by default. A
+// quote of 0 disables quoted strings, so the quote character lexes as part
+// of a plain string.
+func (l *Lexer) SetQuote(quote rune) {
+	l.quote = quote
+}
+
+func (l *Lexer) Quote() rune {
+	return l.quote
+}
+
+// SetEscapes sets whether a backslash inside a quoted string escapes the
+// rune that follows it, as in KiCad files. Escapes are off by default, so a
+// backslash is literal, as in Windows paths.
+func (l *Lexer) SetEscapes(escapes bool) {
+	l.escapes = escapes
+}
+
+// SetMaxTokenLength bounds the length in bytes of string tokens, including
+// any quotes, and of runs of whitespace. Longer tokens lex as a TokenErr
+// carrying a *LimitError. 0, the default, means no limit.
+func (l *Lexer) SetMaxTokenLength(n int) {
+	l.maxToken = n
+}
+
+func (l *Lexer) checkTokenLength() error {
+	if l.maxToken > 0 && len(l.content) > l.maxToken {
+		return &LimitError{Kind: LimitTokenLength, Max: int64(l.maxToken), Line: l.startLine, Column: l.startColumn}
+	}
+	return nil
+}
+
 func (l *Lexer) NextToken(token *Token) {
 	l.startLine = l.line
 	l.startColumn = l.column
@@ -48,9 +84,13 @@ func (l *Lexer) NextToken(token *Token) {
 	} else if r == ')' {
 		l.emit(token, TokenClose, nil)
 	} else if unicode.IsSpace(r) {
-		l.acceptWhitespace()
+		err = l.acceptWhitespace()
+		if err != nil {
+			l.emit(token, TokenErr, err)
+			return
+		}
 		l.emit(token, TokenWhitespace, nil)
-	} else if r == '"' {
+	} else if l.quote != 0 && r == l.quote {
 		err = l.acceptQuotedString()
 		if err != nil {
 			l.emit(token, TokenErr, err)
@@ -119,6 +159,9 @@ func (l *Lexer) acceptWhitespace() error {
 		if !unicode.IsSpace(r) {
 			return l.unread()
 		}
+		if err := l.checkTokenLength(); err != nil {
+			return err
+		}
 	}
 }
 
@@ -131,9 +174,21 @@ func (l *Lexer) acceptQuotedString() error {
 		if err != nil {
 			return err
 		}
-		if r == '"' {
+		if err := l.checkTokenLength(); err != nil {
+			return err
+		}
+		if r == l.quote {
 			return nil
 		}
+		if r == '\\' && l.escapes {
+			_, err = l.read()
+			if err == io.EOF {
+				return fmt.Errorf("unterminated quoted string")
+			}
+			if err != nil {
+				return err
+			}
+		}
 	}
 }
 
@@ -149,5 +204,8 @@ func (l *Lexer) acceptString() error {
 		if r == '(' || r == ')' || unicode.IsSpace(r) {
 			return l.unread()
 		}
+		if err := l.checkTokenLength(); err != nil {
+			return err
+		}
 	}
 }
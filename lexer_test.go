@@ -0,0 +1,59 @@
+package sexpr
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func lexAll(l *Lexer) []Token {
+	tokens := []Token{}
+	for {
+		var token Token
+		l.NextToken(&token)
+		if token.Kind == TokenWhitespace {
+			continue
+		}
+		tokens = append(tokens, token)
+		if token.Kind == TokenEOF || token.Kind == TokenErr {
+			return tokens
+		}
+	}
+}
+
+func TestLexerQuote(t *testing.T) {
+	l := NewLexer(bufio.NewReader(strings.NewReader(`'a b' "c`)))
+	require.Equal(t, '"', l.Quote())
+	l.SetQuote('\'')
+	tokens := lexAll(l)
+	require.Equal(t, 3, len(tokens))
+	require.Equal(t, TokenQuotedString, tokens[0].Kind)
+	require.Equal(t, `'a b'`, tokens[0].Content)
+	require.Equal(t, TokenString, tokens[1].Kind)
+	require.Equal(t, `"c`, tokens[1].Content)
+	require.Equal(t, TokenEOF, tokens[2].Kind)
+}
+
+func TestLexerQuoteDisabled(t *testing.T) {
+	l := NewLexer(bufio.NewReader(strings.NewReader(`(string_quote ")`)))
+	l.SetQuote(0)
+	tokens := lexAll(l)
+	require.Equal(t, 5, len(tokens))
+	require.Equal(t, TokenString, tokens[2].Kind)
+	require.Equal(t, `"`, tokens[2].Content)
+	require.Equal(t, TokenClose, tokens[3].Kind)
+}
+
+func TestLexerEscapes(t *testing.T) {
+	l := NewLexer(bufio.NewReader(strings.NewReader(`"C:\dir\" x`)))
+	tokens := lexAll(l)
+	require.Equal(t, `"C:\dir\"`, tokens[0].Content)
+	require.Equal(t, "x", tokens[1].Content)
+
+	l = NewLexer(bufio.NewReader(strings.NewReader(`"C:\dir\" x`)))
+	l.SetEscapes(true)
+	tokens = lexAll(l)
+	require.Equal(t, TokenErr, tokens[0].Kind)
+}
@@ -0,0 +1,24 @@
+package sexpr
+
+type LimitKind int
+
+const (
+	LimitDepth LimitKind = iota
+	LimitTokenLength
+	LimitNodes
+	LimitBytes
+)
+
+func (k LimitKind) String() string {
+	switch k {
+	case LimitDepth:
+		return "nesting depth"
+	case LimitTokenLength:
+		return "token length"
+	case LimitNodes:
+		return "node count"
+	case LimitBytes:
+		return "input size"
+	}
+	return "unknown limit"
+}
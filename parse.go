@@ -14,14 +14,48 @@ type tmpSexpr struct {
 }
 
 func Parse(input *bufio.Reader) (*Sexpr, error) {
-	lexer := NewLexer(input)
+	return ParseLexer(NewLexer(input))
+}
+
+// ParseLexer is like Parse, but reads tokens from a lexer the caller has
+// configured, e.g. with SetEscapes(true).
+func ParseLexer(lexer *Lexer) (*Sexpr, error) {
+	roots, err := parse(lexer, false, nil)
+	if err != nil || len(roots) == 0 {
+		return nil, err
+	}
+	return roots[0], nil
+}
+
+// ParseAll is like Parse, but accepts any number of top-level sexprs and
+// returns them in order.
+func ParseAll(input *bufio.Reader) ([]*Sexpr, error) {
+	return parse(NewLexer(input), true, nil)
+}
+
+// ParseAllLexer is ParseAll reading tokens from a configured lexer.
+func ParseAllLexer(lexer *Lexer) ([]*Sexpr, error) {
+	return parse(lexer, true, nil)
+}
 
+// parse reads sexprs from lexer, checking them against limits when it is
+// not nil. Nodes are linked directly rather than through the setters, which
+// would bump the version of every ancestor for each node and make parsing
+// quadratic in the nesting depth.
+func parse(lexer *Lexer, multi bool, limits *parseLimits) ([]*Sexpr, error) {
+
+	roots := []*Sexpr{}
 	var root *Sexpr = nil
 	var sexpr *Sexpr
 	var token Token
 
 	for {
 		lexer.NextToken(&token)
+		if limits != nil && token.Kind != TokenErr {
+			if err := limits.token(&token); err != nil {
+				return nil, err
+			}
+		}
 
 		if token.Kind == TokenWhitespace {
 			// ignore
@@ -30,9 +64,14 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 			if sexpr != nil && sexpr.Name() == "" {
 				return nil, fmt.Errorf("unexpected open at Line %d, Column %d", token.Line, token.Column)
 			}
-			if sexpr == nil && root != nil {
+			if sexpr == nil && root != nil && !multi {
 				return nil, fmt.Errorf("unexpected open at Line %d, Column %d", token.Line, token.Column)
 			}
+			if limits != nil {
+				if err := limits.open(&token); err != nil {
+					return nil, err
+				}
+			}
 			p := sexpr
 			sexpr = NewSexpr("")
 			sexpr.SetLocation(token.Line, token.Column)
@@ -42,10 +81,11 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 				if err != nil {
 					return nil, err
 				}
-				p.AddParam(len(p.Params()), sp)
+				p.params = append(p.params, sp)
 			}
-			if root == nil {
+			if p == nil {
 				root = sexpr
+				roots = append(roots, root)
 			}
 
 		} else if token.Kind == TokenClose {
@@ -56,14 +96,22 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 				return nil, fmt.Errorf("unexpected close at Line %d, Column %d", token.Line, token.Column)
 			}
 			sexpr = sexpr.Parent()
+			if limits != nil {
+				limits.close()
+			}
 
 		} else if token.Kind == TokenString {
 			if sexpr == nil {
 				return nil, fmt.Errorf("unexpected string at Line %d, Column %d: '%s'", token.Line, token.Column, token.Content)
 			}
 			if sexpr.Name() == "" {
-				sexpr.SetName(token.Content)
+				sexpr.name = token.Content
 			} else {
+				if limits != nil {
+					if err := limits.node(&token); err != nil {
+						return nil, err
+					}
+				}
 				str := NewSexprStringQuoted(token.Content, false)
 				str.SetLocation(token.Line, token.Column)
 				str.SetParent(sexpr)
@@ -71,7 +119,7 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 				if err != nil {
 					return nil, err
 				}
-				sexpr.AddParam(len(sexpr.Params()), param)
+				sexpr.params = append(sexpr.params, param)
 			}
 
 		} else if token.Kind == TokenQuotedString {
@@ -81,6 +129,11 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 			if sexpr.Name() == "" {
 				return nil, fmt.Errorf("unexpected quoted string at Line %d, Column %d: '%s'", token.Line, token.Column, token.Content)
 			}
+			if limits != nil {
+				if err := limits.node(&token); err != nil {
+					return nil, err
+				}
+			}
 			str := NewSexprStringQuoted(token.Content[1:len(token.Content)-1], true)
 			str.SetLocation(token.Line, token.Column)
 			str.SetParent(sexpr)
@@ -88,16 +141,16 @@ func Parse(input *bufio.Reader) (*Sexpr, error) {
 			if err != nil {
 				return nil, err
 			}
-			sexpr.AddParam(len(sexpr.Params()), param)
+			sexpr.params = append(sexpr.params, param)
 
 		} else if token.Kind == TokenEOF {
 			if sexpr != nil {
 				return nil, fmt.Errorf("unexpected EOF at Line %d, Column %d", token.Line, token.Column)
 			}
-			return root, nil
+			return roots, nil
 
 		} else if token.Kind == TokenErr {
-			return nil, fmt.Errorf("error at Line %d, Column %d: %s", token.Line, token.Column, token.Err.Error())
+			return nil, tokenErr(&token)
 
 		}
 	}
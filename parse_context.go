@@ -0,0 +1,160 @@
+package sexpr
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLimitExceeded is matched, via errors.Is, by every *LimitError.
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// LimitError reports input that exceeds one of the ParseOptions limits.
+type LimitError struct {
+	Kind   LimitKind
+	Max    int64
+	Line   int
+	Column int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s limit of %d exceeded at Line %d, Column %d", e.Kind.String(), e.Max, e.Line, e.Column)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// ParseOptions bounds the resources a parse may use. Zero fields mean no
+// limit.
+type ParseOptions struct {
+	// MaxDepth bounds how deeply lists nest; the root is at depth 1.
+	MaxDepth int
+	// MaxTokenLength bounds the length in bytes of a string, including any
+	// quotes, or of a run of whitespace.
+	MaxTokenLength int
+	// MaxNodes bounds the number of lists and strings in the tree.
+	MaxNodes int
+	// MaxBytes bounds the size of the input.
+	MaxBytes int64
+}
+
+// parseLimits tracks a parse against its options and context.
+type parseLimits struct {
+	ctx    context.Context
+	opts   ParseOptions
+	depth  int
+	nodes  int
+	tokens int
+}
+
+// ParseContext is like Parse, but stops with ctx's error once ctx is done
+// and with a *LimitError as soon as the input exceeds a limit in opts, so
+// untrusted input cannot hang or exhaust the caller. Cancellation is
+// noticed between reads and tokens; a Read on r that blocks is not
+// interrupted. A nil opts sets no limits.
+func ParseContext(ctx context.Context, r io.Reader, opts *ParseOptions) (*Sexpr, error) {
+	limits := parseLimits{ctx: ctx}
+	if opts != nil {
+		limits.opts = *opts
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r = &contextReader{ctx: ctx, r: r}
+	if limits.opts.MaxBytes > 0 {
+		r = &limitReader{r: r, left: limits.opts.MaxBytes, max: limits.opts.MaxBytes}
+	}
+	lexer := NewLexer(bufio.NewReader(r))
+	lexer.SetMaxTokenLength(limits.opts.MaxTokenLength)
+	roots, err := parse(lexer, false, &limits)
+	if err != nil || len(roots) == 0 {
+		return nil, err
+	}
+	return roots[0], nil
+}
+
+// token is called for every token read; it checks for cancellation every so
+// often, since ctx.Err takes a lock.
+func (pl *parseLimits) token(token *Token) error {
+	pl.tokens += 1
+	if pl.tokens%256 == 0 {
+		if err := pl.ctx.Err(); err != nil {
+			return fmt.Errorf("parse cancelled at Line %d, Column %d: %w", token.Line, token.Column, err)
+		}
+	}
+	return nil
+}
+
+func (pl *parseLimits) open(token *Token) error {
+	pl.depth += 1
+	if pl.opts.MaxDepth > 0 && pl.depth > pl.opts.MaxDepth {
+		return &LimitError{Kind: LimitDepth, Max: int64(pl.opts.MaxDepth), Line: token.Line, Column: token.Column}
+	}
+	return pl.node(token)
+}
+
+func (pl *parseLimits) close() {
+	pl.depth -= 1
+}
+
+func (pl *parseLimits) node(token *Token) error {
+	pl.nodes += 1
+	if pl.opts.MaxNodes > 0 && pl.nodes > pl.opts.MaxNodes {
+		return &LimitError{Kind: LimitNodes, Max: int64(pl.opts.MaxNodes), Line: token.Line, Column: token.Column}
+	}
+	return nil
+}
+
+// tokenErr passes limit and context errors raised while lexing through
+// unwrapped, locating them at token when they have no location.
+func tokenErr(token *Token) error {
+	var lerr *LimitError
+	if errors.As(token.Err, &lerr) {
+		if lerr.Line == 0 {
+			lerr.Line = token.Line
+			lerr.Column = token.Column
+		}
+		return lerr
+	}
+	return fmt.Errorf("error at Line %d, Column %d: %w", token.Line, token.Column, token.Err)
+}
+
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// limitReader fails with a *LimitError, rather than io.EOF, once more than
+// max bytes have been read.
+type limitReader struct {
+	r    io.Reader
+	left int64
+	max  int64
+}
+
+func (r *limitReader) Read(p []byte) (int, error) {
+	if r.left < 0 {
+		return 0, &LimitError{Kind: LimitBytes, Max: r.max}
+	}
+	// read one byte past the limit to tell a file of exactly max bytes
+	// from a longer one
+	if int64(len(p)) > r.left+1 {
+		p = p[:r.left+1]
+	}
+	n, err := r.r.Read(p)
+	r.left -= int64(n)
+	if r.left < 0 {
+		return n - int(-r.left), &LimitError{Kind: LimitBytes, Max: r.max}
+	}
+	return n, err
+}
@@ -0,0 +1,110 @@
+package sexpr
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const limitInput = `(footprint "R_0603" (layer F.Cu) (pad 1 smd (at 0 0)))`
+
+func TestParseContext(t *testing.T) {
+	root, err := ParseContext(context.Background(), strings.NewReader(limitInput), nil)
+	require.NoError(t, err)
+	require.Equal(t, "footprint", root.Name())
+
+	// limits that are not exceeded do not change the result
+	opts := &ParseOptions{MaxDepth: 3, MaxTokenLength: 9, MaxNodes: 10, MaxBytes: int64(len(limitInput))}
+	root, err = ParseContext(context.Background(), strings.NewReader(limitInput), opts)
+	require.NoError(t, err)
+	require.Equal(t, `(footprint R_0603 (layer F.Cu) (pad 1 smd (at 0 0)))`, string(root.MarshalCanonical(nil)))
+
+	root, err = ParseContext(context.Background(), strings.NewReader("  "), opts)
+	require.NoError(t, err)
+	require.Nil(t, root)
+
+	_, err = ParseContext(context.Background(), strings.NewReader("(a"), opts)
+	require.ErrorContains(t, err, "unexpected EOF")
+	require.False(t, errors.Is(err, ErrLimitExceeded))
+}
+
+func TestParseContextLimits(t *testing.T) {
+	cases := []struct {
+		opts    ParseOptions
+		kind    LimitKind
+		message string
+	}{
+		{ParseOptions{MaxDepth: 2}, LimitDepth, "nesting depth limit of 2 exceeded at Line 1, Column 45"},
+		{ParseOptions{MaxTokenLength: 7}, LimitTokenLength, "token length limit of 7 exceeded at Line 1, Column 2"},
+		{ParseOptions{MaxNodes: 9}, LimitNodes, "node count limit of 9 exceeded at Line 1, Column 51"},
+		{ParseOptions{MaxBytes: int64(len(limitInput)) - 1}, LimitBytes, "input size limit of 53 exceeded at Line 1, Column 54"},
+	}
+	for _, c := range cases {
+		_, err := ParseContext(context.Background(), strings.NewReader(limitInput), &c.opts)
+		require.ErrorIs(t, err, ErrLimitExceeded)
+		var lerr *LimitError
+		require.True(t, errors.As(err, &lerr))
+		require.Equal(t, c.kind, lerr.Kind)
+		require.Equal(t, c.message, err.Error())
+	}
+}
+
+// endless never ends, like a stalled or malicious upload.
+type endless struct {
+	pattern string
+	pos     int
+}
+
+func (e *endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = e.pattern[e.pos%len(e.pattern)]
+		e.pos += 1
+	}
+	return len(p), nil
+}
+
+func TestParseContextUnbounded(t *testing.T) {
+	_, err := ParseContext(context.Background(), io.MultiReader(strings.NewReader("(a "), &endless{pattern: "b "}), &ParseOptions{MaxBytes: 1 << 20})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = ParseContext(context.Background(), &endless{pattern: "(a "}, &ParseOptions{MaxDepth: 1000})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = ParseContext(context.Background(), io.MultiReader(strings.NewReader(`(a "`), &endless{pattern: "b"}), &ParseOptions{MaxTokenLength: 4096})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	_, err = ParseContext(context.Background(), io.MultiReader(strings.NewReader(`(a`), &endless{pattern: " \n"}), &ParseOptions{MaxTokenLength: 4096})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+func TestParseContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ParseContext(ctx, strings.NewReader(limitInput), nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	r := &cancelAfter{r: io.MultiReader(strings.NewReader("(a "), &endless{pattern: "b "}), n: 10, cancel: cancel}
+	_, err = ParseContext(ctx, r, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, errors.Is(err, ErrLimitExceeded))
+}
+
+// cancelAfter cancels its context after n reads.
+type cancelAfter struct {
+	r      io.Reader
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfter) Read(p []byte) (int, error) {
+	c.n -= 1
+	if c.n == 0 {
+		c.cancel()
+	}
+	return c.r.Read(p)
+}
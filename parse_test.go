@@ -72,6 +72,49 @@ func TestParseNotNested(t *testing.T) {
 	require.ErrorContains(t, err, "unexpected open")
 }
 
+func TestParseAll(t *testing.T) {
+	roots, err := ParseAll(bufio.NewReader(strings.NewReader("(a)\n(b c (d))\n  (e)")))
+	require.NoError(t, err)
+	require.Equal(t, 3, len(roots))
+	assertSexpr(t, roots[0], "a", 0)
+	assertSexpr(t, roots[1], "b", 2)
+	assertSexpr(t, roots[2], "e", 0)
+	require.Nil(t, roots[1].Parent())
+	line, col := roots[2].Location()
+	require.Equal(t, 3, line)
+	require.Equal(t, 3, col)
+
+	roots, err = ParseAll(bufio.NewReader(strings.NewReader("  ")))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(roots))
+
+	_, err = ParseAll(bufio.NewReader(strings.NewReader("(a) b")))
+	require.ErrorContains(t, err, "unexpected string")
+
+	_, err = ParseAll(bufio.NewReader(strings.NewReader("(a) (b")))
+	require.ErrorContains(t, err, "unexpected EOF")
+
+	lexer := NewLexer(bufio.NewReader(strings.NewReader(`(a "x\"") (b)`)))
+	lexer.SetEscapes(true)
+	roots, err = ParseAllLexer(lexer)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(roots))
+	assertStringParam(t, roots[0].Params()[0], `x\"`, true)
+}
+
+func TestParseLexer(t *testing.T) {
+	// backslashes are literal by default
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a "C:\dir\" b)`)))
+	require.NoError(t, err)
+	assertSexpr(t, root, "a", 2)
+	assertStringParam(t, root.Params()[0], `C:\dir\`, true)
+
+	lexer := NewLexer(bufio.NewReader(strings.NewReader(`(a "C:\dir\" b)`)))
+	lexer.SetEscapes(true)
+	_, err = ParseLexer(lexer)
+	require.ErrorContains(t, err, "unterminated quoted string")
+}
+
 func TestSerialize(t *testing.T) {
 	root, err := Parse(bufio.NewReader(strings.NewReader(`(a b "c c" #$% 1 2.3)`)))
 	require.Nil(t, err)
@@ -89,6 +132,53 @@ func TestSerialize(t *testing.T) {
 	require.Equal(t, "(a\n\t(b\n\t\t(c d)\n\t)\n)", root.String())
 }
 
+func TestParseEscapedQuote(t *testing.T) {
+	root := parseEscaped(t, `(a "x\"y" "z\\")`)
+	assertSexpr(t, root, "a", 2)
+	assertStringParam(t, root.Params()[0], `x\"y`, true)
+	assertStringParam(t, root.Params()[1], `z\\`, true)
+	require.Equal(t, `(a "x\"y" "z\\")`, root.String())
+
+	lexer := NewLexer(bufio.NewReader(strings.NewReader(`(a "x\")`)))
+	lexer.SetEscapes(true)
+	_, err := ParseLexer(lexer)
+	require.ErrorContains(t, err, "unterminated quoted string")
+}
+
+func TestSerializeNoParams(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a)`)))
+	require.Nil(t, err)
+	require.Equal(t, "(a)", root.String())
+
+	root, err = Parse(bufio.NewReader(strings.NewReader(`(a (b) (c d))`)))
+	require.Nil(t, err)
+	require.Equal(t, "(a\n\t(b)\n\t(c d)\n)", root.String())
+
+	// empty children in every position, with no blank lines between them
+	root, err = Parse(bufio.NewReader(strings.NewReader(`(a x (b) (c (d)) (e))`)))
+	require.Nil(t, err)
+	require.Equal(t, "(a x\n\t(b)\n\t(c\n\t\t(d)\n\t)\n\t(e)\n)", root.String())
+	require.NotContains(t, root.String(), "\n\n")
+}
+
+func TestParseDeep(t *testing.T) {
+	depth := 16000
+	input := strings.Repeat("(a ", depth) + strings.Repeat(")", depth)
+	root, err := Parse(bufio.NewReader(strings.NewReader(input)))
+	require.Nil(t, err)
+
+	// building the tree does not count as modifying it
+	require.Equal(t, uint64(0), root.Version())
+	leaf := root
+	for i := 1; i < depth; i++ {
+		leaf = leaf.Params()[0].Value().(*Sexpr)
+	}
+	require.Equal(t, 0, len(leaf.Params()))
+
+	leaf.SetName("b")
+	require.Equal(t, uint64(1), root.Version())
+}
+
 // ---
 
 func assertSexpr(t *testing.T, s *Sexpr, name string, params int) {
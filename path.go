@@ -0,0 +1,246 @@
+package sexpr
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path returns the address of the sexpr from its root, e.g.
+// /kicad_pcb/footprint[12]/pad[3]. Indexes are zero based and count only
+// siblings with the same name. Names containing any of /[]=" or a backslash
+// are written as Go quoted strings, e.g. /root/"a/b"[0].
+func (s *Sexpr) Path() string {
+	return s.PathKeyed()
+}
+
+// PathKeyed is like Path, but addresses a sexpr as name[key=value] instead of
+// by position when it has a direct child named key, e.g.
+// /kicad_pcb/footprint[uuid=...]. Keys are tried in order.
+func (s *Sexpr) PathKeyed(keys ...string) string {
+	segments := []string{}
+	for sexpr := s; sexpr != nil; sexpr = sexpr.parent {
+		segments = append(segments, sexpr.pathSegment(keys))
+	}
+	var sb strings.Builder
+	for i := len(segments) - 1; i >= 0; i-- {
+		sb.WriteString("/")
+		sb.WriteString(segments[i])
+	}
+	return sb.String()
+}
+
+func (s *Sexpr) pathSegment(keys []string) string {
+	name := s.Name()
+	if s.parent == nil {
+		return quotePathName(name)
+	}
+	for _, key := range keys {
+		if v, ok := pathKeyValue(s, key); ok {
+			return quotePathName(name) + "[" + quotePathName(key) + "=" + quotePathValue(v) + "]"
+		}
+	}
+	idx := 0
+	for _, param := range s.parent.params {
+		sibling, ok := param.Value().(*Sexpr)
+		if !ok {
+			continue
+		}
+		if sibling == s {
+			break
+		}
+		if sibling.Name() == name {
+			idx += 1
+		}
+	}
+	return quotePathName(name) + "[" + strconv.Itoa(idx) + "]"
+}
+
+func pathKeyValue(s *Sexpr, key string) (string, bool) {
+	child := s.FindDirectChildByNameExact(key)
+	if child == nil || len(child.params) == 0 {
+		return "", false
+	}
+	v, err := child.params[0].AsString()
+	if err != nil {
+		return "", false
+	}
+	return v, true
+}
+
+func quotePathName(name string) string {
+	if name == "" || strings.ContainsAny(name, "/[]=\"\\") {
+		return strconv.Quote(name)
+	}
+	return name
+}
+
+func quotePathValue(v string) string {
+	if v == "" || strings.ContainsAny(v, "/[]=\"\\") || shouldQuote(v) {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+type pathSegment struct {
+	name  string
+	index int
+	key   string
+	value string
+}
+
+// Resolve returns the sexpr addressed by path, as produced by Path or
+// PathKeyed, starting from root. A segment without an index selects the
+// first sexpr with that name.
+func Resolve(root *Sexpr, path string) (*Sexpr, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil || segments[0].name != root.Name() {
+		return nil, fmt.Errorf("path root '%s' not found", segments[0].name)
+	}
+	sexpr := root
+	for _, seg := range segments[1:] {
+		next := sexpr.resolveSegment(seg)
+		if next == nil {
+			return nil, fmt.Errorf("path segment '%s' not found under %s", seg.String(), sexpr.Path())
+		}
+		sexpr = next
+	}
+	return sexpr, nil
+}
+
+func (s *Sexpr) resolveSegment(seg pathSegment) *Sexpr {
+	idx := 0
+	for _, param := range s.params {
+		child, ok := param.Value().(*Sexpr)
+		if !ok || child.Name() != seg.name {
+			continue
+		}
+		if seg.key != "" {
+			if v, ok := pathKeyValue(child, seg.key); ok && v == seg.value {
+				return child
+			}
+			continue
+		}
+		if idx == seg.index {
+			return child
+		}
+		idx += 1
+	}
+	return nil
+}
+
+func (seg pathSegment) String() string {
+	if seg.key != "" {
+		return quotePathName(seg.name) + "[" + quotePathName(seg.key) + "=" + quotePathValue(seg.value) + "]"
+	}
+	return quotePathName(seg.name) + "[" + strconv.Itoa(seg.index) + "]"
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, errors.New("path must start with '/'")
+	}
+	segments := []pathSegment{}
+	rest := path
+	for len(rest) > 0 {
+		if rest[0] != '/' {
+			return nil, fmt.Errorf("invalid path '%s': expected '/' at offset %d", path, len(path)-len(rest))
+		}
+		seg := pathSegment{}
+		var err error
+		seg.name, _, rest, err = parsePathName(rest[1:], "/[")
+		if err != nil {
+			return nil, fmt.Errorf("invalid path '%s': %s", path, err.Error())
+		}
+		if strings.HasPrefix(rest, "[") {
+			rest, err = parsePathSelector(rest[1:], &seg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path '%s': %s", path, err.Error())
+			}
+		}
+		segments = append(segments, seg)
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path '%s': no segments", path)
+	}
+	return segments, nil
+}
+
+// parsePathName reads a name, quoted or ending before any of stop, from the
+// start of rest. It reports whether the name was quoted and returns what
+// follows it.
+func parsePathName(rest string, stop string) (string, bool, string, error) {
+	if strings.HasPrefix(rest, `"`) {
+		prefix, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", false, "", errors.New("invalid quoted name")
+		}
+		name, _ := strconv.Unquote(prefix)
+		return name, true, rest[len(prefix):], nil
+	}
+	end := strings.IndexAny(rest, stop)
+	if end == -1 {
+		end = len(rest)
+	}
+	if end == 0 {
+		return "", false, "", errors.New("empty name")
+	}
+	return rest[:end], false, rest[end:], nil
+}
+
+func parsePathSelector(rest string, seg *pathSegment) (string, error) {
+	if strings.HasPrefix(rest, `"`) {
+		key, _, after, err := parsePathName(rest, "=]")
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(after, "=") {
+			return "", errors.New("unterminated selector")
+		}
+		seg.key = key
+		return parsePathValue(after[1:], seg)
+	}
+	eq := strings.IndexAny(rest, "=]")
+	if eq == -1 {
+		return "", errors.New("unterminated selector")
+	}
+	if rest[eq] == ']' {
+		idx, err := strconv.Atoi(rest[:eq])
+		if err != nil || idx < 0 {
+			return "", fmt.Errorf("invalid index '%s'", rest[:eq])
+		}
+		seg.index = idx
+		return rest[eq+1:], nil
+	}
+
+	seg.key = rest[:eq]
+	if seg.key == "" {
+		return "", errors.New("empty key")
+	}
+	return parsePathValue(rest[eq+1:], seg)
+}
+
+func parsePathValue(rest string, seg *pathSegment) (string, error) {
+	if strings.HasPrefix(rest, `"`) {
+		prefix, err := strconv.QuotedPrefix(rest)
+		if err != nil {
+			return "", errors.New("invalid quoted value")
+		}
+		seg.value, _ = strconv.Unquote(prefix)
+		rest = rest[len(prefix):]
+		if !strings.HasPrefix(rest, "]") {
+			return "", errors.New("unterminated selector")
+		}
+		return rest[1:], nil
+	}
+	end := strings.Index(rest, "]")
+	if end == -1 {
+		return "", errors.New("unterminated selector")
+	}
+	seg.value = rest[:end]
+	return rest[end+1:], nil
+}
@@ -0,0 +1,105 @@
+package sexpr
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const pathTestInput = `(kicad_pcb
+	(net 0 "")
+	(footprint R1 (uuid "a/1") (pad 1) (pad 2))
+	(footprint C1 (uuid b2) (pad 1) (pad 2) (pad 3))
+)`
+
+func TestPath(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(pathTestInput)))
+	require.NoError(t, err)
+
+	require.Equal(t, "/kicad_pcb", root.Path())
+
+	fps := root.FindDirectChildrenByName("footprint")
+	require.Equal(t, "/kicad_pcb/footprint[1]", fps[1].Path())
+
+	pad := fps[1].FindDirectChildrenByName("pad")[2]
+	require.Equal(t, "/kicad_pcb/footprint[1]/pad[2]", pad.Path())
+	require.Equal(t, "/kicad_pcb/footprint[uuid=b2]/pad[2]", pad.PathKeyed("uuid"))
+	require.Equal(t, `/kicad_pcb/footprint[uuid="a/1"]`, fps[0].PathKeyed("tstamp", "uuid"))
+}
+
+func TestPathQuotedNames(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(root (a/b 1) (x[0] (k=v 2)) (q"r (id 7)))`)))
+	require.NoError(t, err)
+
+	children := root.FindChildren(func(s *Sexpr, d int) bool { return true }, -1)
+	require.Equal(t, []string{
+		`/root/"a/b"[0]`,
+		`/root/"x[0]"[0]`,
+		`/root/"q\"r"[0]`,
+		`/root/"x[0]"[0]/"k=v"[0]`,
+		`/root/"q\"r"[0]/id[0]`,
+	}, func() []string {
+		paths := []string{}
+		for _, c := range children {
+			paths = append(paths, c.Path())
+		}
+		return paths
+	}())
+	for _, c := range children {
+		found, err := Resolve(root, c.Path())
+		require.NoError(t, err)
+		require.Same(t, c, found)
+	}
+
+	x := root.FindDirectChildByNameExact("x[0]")
+	require.Equal(t, `/root/"x[0]"["k=v"=2]`, x.PathKeyed("k=v"))
+	found, err := Resolve(root, x.PathKeyed("k=v"))
+	require.NoError(t, err)
+	require.Same(t, x, found)
+
+	_, err = Resolve(root, `/root/"a/b`)
+	require.ErrorContains(t, err, "invalid quoted name")
+}
+
+func TestResolve(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(pathTestInput)))
+	require.NoError(t, err)
+
+	for _, sexpr := range root.FindChildren(func(s *Sexpr, d int) bool { return true }, -1) {
+		found, err := Resolve(root, sexpr.Path())
+		require.NoError(t, err)
+		require.Same(t, sexpr, found)
+
+		found, err = Resolve(root, sexpr.PathKeyed("uuid"))
+		require.NoError(t, err)
+		require.Same(t, sexpr, found)
+	}
+
+	found, err := Resolve(root, "/kicad_pcb/footprint/pad[1]")
+	require.NoError(t, err)
+	require.Equal(t, "2", found.Params()[0].String())
+
+	found, err = Resolve(root, `/kicad_pcb/footprint[uuid="a/1"]`)
+	require.NoError(t, err)
+	require.Equal(t, "R1", found.Params()[0].String())
+
+	_, err = Resolve(root, "/kicad_pcb/footprint[2]")
+	require.ErrorContains(t, err, "not found")
+
+	_, err = Resolve(root, "/other")
+	require.ErrorContains(t, err, "not found")
+
+	_, err = Resolve(root, "kicad_pcb")
+	require.ErrorContains(t, err, "must start with")
+
+	_, err = Resolve(root, "/kicad_pcb/footprint[x]")
+	require.ErrorContains(t, err, "invalid index")
+
+	_, err = Resolve(root, "/kicad_pcb/footprint[uuid=b2")
+	require.ErrorContains(t, err, "unterminated")
+
+	_, err = Resolve(root, "/kicad_pcb//pad")
+	require.ErrorContains(t, err, "empty name")
+}
@@ -0,0 +1,212 @@
+package schema
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Load reads a schema written as an s-expression:
+//
+//	(schema NAME
+//	  (define NAME BODY...)...
+//	  (root NAME BODY...))
+//
+// where a node BODY is any of the flags required, optional, repeated,
+// one_or_more, ordered and open, (use DEFINITION), (param TYPE [optional]
+// [repeated] [(name NAME)]) and (child NAME BODY...). TYPE is one of any,
+// string, int and float, or (enum VALUE...).
+func Load(r io.Reader) (*Schema, error) {
+	root, err := sexpr.Parse(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, errors.New("empty document")
+	}
+	return FromSexpr(root)
+}
+
+func LoadFile(path string) (*Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s, err := Load(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+func FromSexpr(root *sexpr.Sexpr) (*Schema, error) {
+	if root.Name() != "schema" {
+		return nil, errorAt(root, "unexpected root '%s', expected 'schema'", root.Name())
+	}
+	name, err := firstAtom(root)
+	if err != nil {
+		return nil, err
+	}
+	s := New(name, nil)
+	for _, param := range root.Params()[1:] {
+		child, ok := param.Value().(*sexpr.Sexpr)
+		if !ok {
+			return nil, errorAt(root, "unexpected '%s' in schema", param.String())
+		}
+		n, err := loadNode(child)
+		if err != nil {
+			return nil, err
+		}
+		switch child.Name() {
+		case "define":
+			s.Define(n.Name, n)
+		case "root":
+			if s.Root != nil {
+				return nil, errorAt(child, "duplicate root")
+			}
+			s.Root = n
+		default:
+			return nil, errorAt(child, "unexpected '%s' in schema", child.Name())
+		}
+	}
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func loadNode(s *sexpr.Sexpr) (*Node, error) {
+	name, err := firstAtom(s)
+	if err != nil {
+		return nil, err
+	}
+	n := Node{Name: name, Params: []Param{}, Children: []*Node{}}
+	for _, param := range s.Params()[1:] {
+		switch pv := param.Value().(type) {
+		case *sexpr.SexprString:
+			switch pv.Value() {
+			case "optional":
+				n.Occurs = OccursOptional
+			case "required":
+				n.Occurs = OccursRequired
+			case "repeated":
+				n.Occurs = OccursRepeated
+			case "one_or_more":
+				n.Occurs = OccursOneOrMore
+			case "ordered":
+				n.Ordered = true
+			case "open":
+				n.Open = true
+			default:
+				return nil, errorAtAtom(pv, "unknown flag '%s'", pv.Value())
+			}
+		case *sexpr.Sexpr:
+			switch pv.Name() {
+			case "param":
+				p, err := loadParam(pv)
+				if err != nil {
+					return nil, err
+				}
+				n.Params = append(n.Params, p)
+			case "child":
+				child, err := loadNode(pv)
+				if err != nil {
+					return nil, err
+				}
+				n.Children = append(n.Children, child)
+			case "use":
+				n.Ref, err = firstAtom(pv)
+				if err != nil {
+					return nil, err
+				}
+			default:
+				return nil, errorAt(pv, "unexpected '%s' in '%s'", pv.Name(), name)
+			}
+		}
+	}
+	return &n, nil
+}
+
+func loadParam(s *sexpr.Sexpr) (Param, error) {
+	p := Param{}
+	if len(s.Params()) == 0 {
+		return p, errorAt(s, "param without type")
+	}
+	switch tv := s.Params()[0].Value().(type) {
+	case *sexpr.SexprString:
+		switch tv.Value() {
+		case "any":
+			p.Type = ParamAny
+		case "string":
+			p.Type = ParamString
+		case "int":
+			p.Type = ParamInt
+		case "float":
+			p.Type = ParamFloat
+		default:
+			return p, errorAtAtom(tv, "unknown param type '%s'", tv.Value())
+		}
+	case *sexpr.Sexpr:
+		if tv.Name() != "enum" {
+			return p, errorAt(tv, "unknown param type '%s'", tv.Name())
+		}
+		p.Type = ParamEnum
+		p.Enum = []string{}
+		for _, param := range tv.Params() {
+			v, err := param.AsString()
+			if err != nil {
+				return p, errorAt(tv, "enum values must be strings")
+			}
+			p.Enum = append(p.Enum, v)
+		}
+	}
+	for _, param := range s.Params()[1:] {
+		switch pv := param.Value().(type) {
+		case *sexpr.SexprString:
+			switch pv.Value() {
+			case "optional":
+				p.Optional = true
+			case "repeated":
+				p.Repeated = true
+			default:
+				return p, errorAtAtom(pv, "unknown param flag '%s'", pv.Value())
+			}
+		case *sexpr.Sexpr:
+			if pv.Name() != "name" {
+				return p, errorAt(pv, "unexpected '%s' in param", pv.Name())
+			}
+			name, err := firstAtom(pv)
+			if err != nil {
+				return p, err
+			}
+			p.Name = name
+		}
+	}
+	return p, nil
+}
+
+func firstAtom(s *sexpr.Sexpr) (string, error) {
+	if len(s.Params()) == 0 {
+		return "", errorAt(s, "missing name in '%s'", s.Name())
+	}
+	v, err := s.Params()[0].AsString()
+	if err != nil {
+		return "", errorAt(s, "missing name in '%s'", s.Name())
+	}
+	return v, nil
+}
+
+func errorAt(s *sexpr.Sexpr, format string, args ...any) error {
+	line, col := s.Location()
+	return fmt.Errorf("%s at Line %d, Column %d", fmt.Sprintf(format, args...), line, col)
+}
+
+func errorAtAtom(ss *sexpr.SexprString, format string, args ...any) error {
+	line, col := ss.Location()
+	return fmt.Errorf("%s at Line %d, Column %d", fmt.Sprintf(format, args...), line, col)
+}
@@ -0,0 +1,39 @@
+package schema
+
+// Occurrence is how many times a child may appear in its parent.
+type Occurrence int
+
+const (
+	OccursOptional Occurrence = iota
+	OccursRequired
+	OccursRepeated
+	OccursOneOrMore
+)
+
+func (o Occurrence) String() string {
+	switch o {
+	case OccursRequired:
+		return "required"
+	case OccursRepeated:
+		return "repeated"
+	case OccursOneOrMore:
+		return "one_or_more"
+	default:
+		return "optional"
+	}
+}
+
+func (o Occurrence) min() int {
+	if o == OccursRequired || o == OccursOneOrMore {
+		return 1
+	}
+	return 0
+}
+
+// max returns the most times a child may appear, or -1 for no limit.
+func (o Occurrence) max() int {
+	if o == OccursRepeated || o == OccursOneOrMore {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,26 @@
+package schema
+
+type ParamType int
+
+const (
+	ParamAny ParamType = iota
+	ParamString
+	ParamInt
+	ParamFloat
+	ParamEnum
+)
+
+func (t ParamType) String() string {
+	switch t {
+	case ParamString:
+		return "string"
+	case ParamInt:
+		return "int"
+	case ParamFloat:
+		return "float"
+	case ParamEnum:
+		return "enum"
+	default:
+		return "any"
+	}
+}
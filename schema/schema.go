@@ -0,0 +1,122 @@
+// Package schema describes the expected shape of Sexpr documents and
+// validates trees against it.
+//
+// A schema is a tree of Nodes, each naming a list and giving its positional
+// string params and the children it may contain. Schemas can be built in Go
+// or loaded from an s-expression file, see Load.
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Param describes a positional string param. Optional params may only be
+// followed by other optional params; a Repeated param takes all remaining
+// values and must be last. Enum lists the allowed values of a ParamEnum.
+type Param struct {
+	Name     string
+	Type     ParamType
+	Enum     []string
+	Optional bool
+	Repeated bool
+}
+
+// Node describes a list named Name. Children lists the lists it may
+// contain; unknown children are violations unless Open is set. With
+// Ordered set, children must appear in the order they are listed.
+//
+// A node with Ref set takes its params, children and flags from the
+// schema definition of that name, which allows shared and recursive
+// structures. Its own Name and Occurs still apply.
+type Node struct {
+	Name     string
+	Occurs   Occurrence
+	Params   []Param
+	Children []*Node
+	Ordered  bool
+	Open     bool
+	Ref      string
+}
+
+type Schema struct {
+	Name string
+	Root *Node
+	Defs map[string]*Node
+}
+
+func New(name string, root *Node) *Schema {
+	return &Schema{Name: name, Root: root, Defs: map[string]*Node{}}
+}
+
+// Define adds a named node definition for nodes to refer to with Ref.
+func (s *Schema) Define(name string, n *Node) {
+	s.Defs[name] = n
+}
+
+// Check reports problems with the schema itself: undefined references and
+// misplaced optional or repeated params.
+func (s *Schema) Check() error {
+	if s.Root == nil {
+		return fmt.Errorf("schema '%s' has no root", s.Name)
+	}
+	names := []string{}
+	for name := range s.Defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := s.check(s.Defs[name], map[*Node]bool{}); err != nil {
+			return err
+		}
+	}
+	return s.check(s.Root, map[*Node]bool{})
+}
+
+func (s *Schema) check(n *Node, seen map[*Node]bool) error {
+	if seen[n] {
+		return nil
+	}
+	seen[n] = true
+	if n.Ref != "" {
+		if _, ok := s.Defs[n.Ref]; !ok {
+			return fmt.Errorf("schema '%s': node '%s' refers to undefined '%s'", s.Name, n.Name, n.Ref)
+		}
+		return nil
+	}
+	optional := false
+	for i, p := range n.Params {
+		if p.Repeated && i != len(n.Params)-1 {
+			return fmt.Errorf("schema '%s': node '%s': repeated param %d must be last", s.Name, n.Name, i)
+		}
+		if optional && !p.Optional && !p.Repeated {
+			return fmt.Errorf("schema '%s': node '%s': required param %d follows an optional one", s.Name, n.Name, i)
+		}
+		optional = optional || p.Optional
+	}
+	for _, child := range n.Children {
+		if err := s.check(child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the node that holds n's params and children.
+func (s *Schema) resolve(n *Node) *Node {
+	if n.Ref != "" {
+		if def, ok := s.Defs[n.Ref]; ok {
+			return def
+		}
+	}
+	return n
+}
+
+func (n *Node) child(name string) (*Node, int) {
+	for i, child := range n.Children {
+		if child.Name == name {
+			return child, i
+		}
+	}
+	return nil, -1
+}
@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const libTableSchema = `(schema lib_table
+  (define lib
+    ordered
+    (child name required (param string))
+    (child type required (param (enum KiCad Legacy Eagle)))
+    (child uri required (param string))
+    (child options (param string))
+    (child descr (param string))
+    (child disabled))
+  (root fp_lib_table
+    (child version (param int))
+    (child lib repeated (use lib))))`
+
+func TestLoad(t *testing.T) {
+	s, err := Load(strings.NewReader(libTableSchema))
+	require.NoError(t, err)
+
+	require.Equal(t, "lib_table", s.Name)
+	require.Equal(t, "fp_lib_table", s.Root.Name)
+	require.Equal(t, 2, len(s.Root.Children))
+	require.Equal(t, &Node{Name: "lib", Occurs: OccursRepeated, Params: []Param{}, Children: []*Node{}, Ref: "lib"}, s.Root.Children[1])
+
+	lib := s.Defs["lib"]
+	require.True(t, lib.Ordered)
+	require.Equal(t, 6, len(lib.Children))
+	require.Equal(t, OccursRequired, lib.Children[0].Occurs)
+	require.Equal(t, []Param{{Type: ParamEnum, Enum: []string{"KiCad", "Legacy", "Eagle"}}}, lib.Children[1].Params)
+	require.Equal(t, OccursOptional, lib.Children[5].Occurs)
+}
+
+func TestLoadParams(t *testing.T) {
+	s, err := Load(strings.NewReader(`(schema x (root pts
+  (param float (name x)) (param float optional (name y)) (param any repeated)))`))
+	require.NoError(t, err)
+	require.Equal(t, []Param{
+		{Name: "x", Type: ParamFloat},
+		{Name: "y", Type: ParamFloat, Optional: true},
+		{Type: ParamAny, Repeated: true},
+	}, s.Root.Params)
+}
+
+func TestLoadErrors(t *testing.T) {
+	for input, want := range map[string]string{
+		`(other)`:                                              "unexpected root 'other', expected 'schema'",
+		`(schema)`:                                             "missing name in 'schema' at Line 1, Column 1",
+		`(schema x)`:                                           "schema 'x' has no root",
+		`(schema x (root a) (root b))`:                         "duplicate root at Line 1, Column 20",
+		`(schema x (node a))`:                                  "unexpected 'node' in schema",
+		`(schema x (root a sometimes))`:                        "unknown flag 'sometimes' at Line 1, Column 19",
+		`(schema x (root a (param)))`:                          "param without type",
+		`(schema x (root a (param bool)))`:                     "unknown param type 'bool'",
+		`(schema x (root a (param int maybe)))`:                "unknown param flag 'maybe'",
+		`(schema x (root a (child b (use c))))`:                "node 'b' refers to undefined 'c'",
+		`(schema x (root a (param int repeated) (param int)))`: "repeated param 0 must be last",
+		`(schema x (root a (param int optional) (param int)))`: "required param 1 follows an optional one",
+	} {
+		_, err := Load(strings.NewReader(input))
+		require.ErrorContains(t, err, want, input)
+	}
+}
+
+func TestSexprRoundTrip(t *testing.T) {
+	s, err := Load(strings.NewReader(libTableSchema))
+	require.NoError(t, err)
+
+	var sb strings.Builder
+	require.NoError(t, s.Save(&sb))
+	again, err := Load(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	require.Equal(t, s, again)
+	require.Contains(t, sb.String(), "(child type required\n")
+	require.Contains(t, sb.String(), "(enum KiCad Legacy Eagle)")
+}
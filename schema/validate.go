@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Violation is a place where a document does not match its schema.
+type Violation struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("Line %d, Column %d: %s: %s", v.Line, v.Column, v.Path, v.Message)
+}
+
+// ValidationError holds every violation found in a document, in document
+// order.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := []string{}
+	for _, v := range e.Violations {
+		lines = append(lines, v.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+type validator struct {
+	schema     *Schema
+	violations []Violation
+}
+
+// Validate checks root against the schema and returns a *ValidationError
+// listing all violations, or nil when the document matches. An invalid
+// schema is reported as a plain error.
+func Validate(root *sexpr.Sexpr, s *Schema) error {
+	if err := s.Check(); err != nil {
+		return err
+	}
+	v := validator{schema: s, violations: []Violation{}}
+	if root.Name() != s.Root.Name {
+		v.report(root, "unexpected root '%s', expected '%s'", root.Name(), s.Root.Name)
+	} else {
+		v.validate(root, s.Root)
+	}
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+func (v *validator) report(s *sexpr.Sexpr, format string, args ...any) {
+	line, col := s.Location()
+	v.violations = append(v.violations, Violation{Path: s.Path(), Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) reportAtom(s *sexpr.Sexpr, ss *sexpr.SexprString, format string, args ...any) {
+	line, col := ss.Location()
+	v.violations = append(v.violations, Violation{Path: s.Path(), Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(s *sexpr.Sexpr, n *Node) {
+	n = v.schema.resolve(n)
+
+	atoms := []*sexpr.SexprString{}
+	children := []*sexpr.Sexpr{}
+	for _, param := range s.Params() {
+		switch pv := param.Value().(type) {
+		case *sexpr.SexprString:
+			atoms = append(atoms, pv)
+		case *sexpr.Sexpr:
+			children = append(children, pv)
+		}
+	}
+	v.validateParams(s, n, atoms)
+
+	counts := map[string]int{}
+	lastIdx := -1
+	var last *sexpr.Sexpr
+	for _, child := range children {
+		cn, idx := n.child(child.Name())
+		if cn == nil {
+			if !n.Open {
+				v.report(child, "unexpected child '%s' in '%s'", child.Name(), s.Name())
+			}
+			continue
+		}
+		counts[child.Name()] += 1
+		if max := cn.Occurs.max(); max != -1 && counts[child.Name()] == max+1 {
+			v.report(child, "too many '%s' in '%s', at most %d allowed", child.Name(), s.Name(), max)
+		}
+		if n.Ordered {
+			if idx < lastIdx {
+				v.report(child, "'%s' must come before '%s'", child.Name(), last.Name())
+			} else {
+				lastIdx = idx
+				last = child
+			}
+		}
+		v.validate(child, cn)
+	}
+	for _, cn := range n.Children {
+		if min := cn.Occurs.min(); counts[cn.Name] < min {
+			v.report(s, "missing required child '%s'", cn.Name)
+		}
+	}
+}
+
+func (v *validator) validateParams(s *sexpr.Sexpr, n *Node, atoms []*sexpr.SexprString) {
+	required := 0
+	repeated := false
+	for _, p := range n.Params {
+		if !p.Optional && !p.Repeated {
+			required += 1
+		}
+		repeated = repeated || p.Repeated
+	}
+	if len(atoms) < required {
+		v.report(s, "too few params in '%s': got %d, want at least %d", s.Name(), len(atoms), required)
+	}
+	if !repeated && len(atoms) > len(n.Params) {
+		v.report(s, "too many params in '%s': got %d, want at most %d", s.Name(), len(atoms), len(n.Params))
+	}
+	for i, atom := range atoms {
+		var p Param
+		switch {
+		case i < len(n.Params):
+			p = n.Params[i]
+		case repeated:
+			p = n.Params[len(n.Params)-1]
+		default:
+			return
+		}
+		if msg := checkParam(p, atom.Value()); msg != "" {
+			v.reportAtom(s, atom, "param %d%s of '%s': %s", i, paramName(p), s.Name(), msg)
+		}
+	}
+}
+
+func paramName(p Param) string {
+	if p.Name == "" {
+		return ""
+	}
+	return " (" + p.Name + ")"
+}
+
+func checkParam(p Param, value string) string {
+	switch p.Type {
+	case ParamInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Sprintf("'%s' is not an int", value)
+		}
+	case ParamFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("'%s' is not a float", value)
+		}
+	case ParamEnum:
+		for _, e := range p.Enum {
+			if e == value {
+				return ""
+			}
+		}
+		return fmt.Sprintf("'%s' is not one of %s", value, strings.Join(p.Enum, ", "))
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	sexpr "github.com/mlilley/go-sexpr"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, input string) *sexpr.Sexpr {
+	root, err := sexpr.Parse(bufio.NewReader(strings.NewReader(input)))
+	require.NoError(t, err)
+	return root
+}
+
+func violations(t *testing.T, err error) []string {
+	require.Error(t, err)
+	verr, ok := err.(*ValidationError)
+	require.True(t, ok, err.Error())
+	messages := []string{}
+	for _, v := range verr.Violations {
+		messages = append(messages, v.String())
+	}
+	return messages
+}
+
+func TestValidateValid(t *testing.T) {
+	s, err := Load(strings.NewReader(libTableSchema))
+	require.NoError(t, err)
+	root := parse(t, `(fp_lib_table
+  (version 7)
+  (lib (name "A") (type KiCad) (uri "${X}/A.pretty") (options "") (descr ""))
+  (lib (name "B") (type Legacy) (uri "b") (disabled)))`)
+	require.NoError(t, Validate(root, s))
+}
+
+func TestValidateViolations(t *testing.T) {
+	s, err := Load(strings.NewReader(libTableSchema))
+	require.NoError(t, err)
+	root := parse(t, `(fp_lib_table
+  (version seven)
+  (lib (type KiCad) (name "A") (uri "a") (uri "b"))
+  (lib (name "B" "C") (type Altium) (uri "b") (extra)))`)
+
+	require.Equal(t, []string{
+		"Line 2, Column 12: /fp_lib_table/version[0]: param 0 of 'version': 'seven' is not an int",
+		"Line 3, Column 21: /fp_lib_table/lib[0]/name[0]: 'name' must come before 'type'",
+		"Line 3, Column 42: /fp_lib_table/lib[0]/uri[1]: too many 'uri' in 'lib', at most 1 allowed",
+		"Line 4, Column 8: /fp_lib_table/lib[1]/name[0]: too many params in 'name': got 2, want at most 1",
+		"Line 4, Column 29: /fp_lib_table/lib[1]/type[0]: param 0 of 'type': 'Altium' is not one of KiCad, Legacy, Eagle",
+		"Line 4, Column 47: /fp_lib_table/lib[1]/extra[0]: unexpected child 'extra' in 'lib'",
+	}, violations(t, Validate(root, s)))
+
+	root = parse(t, `(fp_lib_table (lib (name)))`)
+	require.Equal(t, []string{
+		"Line 1, Column 20: /fp_lib_table/lib[0]/name[0]: too few params in 'name': got 0, want at least 1",
+		"Line 1, Column 15: /fp_lib_table/lib[0]: missing required child 'type'",
+		"Line 1, Column 15: /fp_lib_table/lib[0]: missing required child 'uri'",
+	}, violations(t, Validate(root, s)))
+
+	root = parse(t, `(sym_lib_table)`)
+	require.Equal(t, []string{
+		"Line 1, Column 1: /sym_lib_table: unexpected root 'sym_lib_table', expected 'fp_lib_table'",
+	}, violations(t, Validate(root, s)))
+}
+
+func TestValidateGoSchema(t *testing.T) {
+	// recursive: a group may hold nested groups
+	s := New("groups", &Node{
+		Name: "doc",
+		Children: []*Node{
+			{Name: "group", Occurs: OccursOneOrMore, Ref: "group"},
+		},
+	})
+	s.Define("group", &Node{
+		Name:   "group",
+		Params: []Param{{Name: "name", Type: ParamString}, {Name: "xy", Type: ParamFloat, Repeated: true}},
+		Open:   true,
+		Children: []*Node{
+			{Name: "group", Occurs: OccursRepeated, Ref: "group"},
+			{Name: "locked", Params: []Param{{Type: ParamEnum, Enum: []string{"yes", "no"}, Optional: true}}},
+		},
+	})
+
+	require.NoError(t, Validate(parse(t, `(doc (group a 1 2.5 -3 (group b (locked) (anything 1 2))))`), s))
+	require.Equal(t, []string{
+		"Line 1, Column 1: /doc: missing required child 'group'",
+	}, violations(t, Validate(parse(t, `(doc)`), s)))
+	require.Equal(t, []string{
+		"Line 1, Column 26: /doc/group[0]/group[0]: param 2 (xy) of 'group': 'x' is not a float",
+		"Line 1, Column 36: /doc/group[0]/group[0]/locked[0]: param 0 of 'locked': 'maybe' is not one of yes, no",
+	}, violations(t, Validate(parse(t, `(doc (group a (group b 1 x (locked maybe))))`), s)))
+
+	s.Define("group", &Node{Name: "group", Children: []*Node{{Name: "x", Ref: "missing"}}})
+	err := Validate(parse(t, `(doc)`), s)
+	require.ErrorContains(t, err, "refers to undefined 'missing'")
+	_, ok := err.(*ValidationError)
+	require.False(t, ok)
+}
@@ -0,0 +1,98 @@
+package schema
+
+import (
+	"io"
+	"sort"
+
+	sexpr "github.com/mlilley/go-sexpr"
+)
+
+// Sexpr returns the schema in the syntax Load reads.
+func (s *Schema) Sexpr() *sexpr.Sexpr {
+	root := sexpr.NewSexpr("schema")
+	addAtom(root, s.Name)
+	names := []string{}
+	for name := range s.Defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := nodeSexpr("define", s.Defs[name])
+		def.Params()[0].SetValue(newAtom(name))
+		addChild(root, def)
+	}
+	if s.Root != nil {
+		addChild(root, nodeSexpr("root", s.Root))
+	}
+	return root
+}
+
+func (s *Schema) Save(w io.Writer) error {
+	_, err := io.WriteString(w, s.Sexpr().String()+"\n")
+	return err
+}
+
+func nodeSexpr(keyword string, n *Node) *sexpr.Sexpr {
+	s := sexpr.NewSexpr(keyword)
+	addAtom(s, n.Name)
+	if n.Occurs != OccursOptional {
+		addAtom(s, n.Occurs.String())
+	}
+	if n.Ordered {
+		addAtom(s, "ordered")
+	}
+	if n.Open {
+		addAtom(s, "open")
+	}
+	if n.Ref != "" {
+		use := sexpr.NewSexpr("use")
+		addAtom(use, n.Ref)
+		addChild(s, use)
+	}
+	for _, p := range n.Params {
+		addChild(s, paramSexpr(p))
+	}
+	for _, child := range n.Children {
+		addChild(s, nodeSexpr("child", child))
+	}
+	return s
+}
+
+func paramSexpr(p Param) *sexpr.Sexpr {
+	s := sexpr.NewSexpr("param")
+	if p.Type == ParamEnum {
+		enum := sexpr.NewSexpr("enum")
+		for _, e := range p.Enum {
+			addAtom(enum, e)
+		}
+		addChild(s, enum)
+	} else {
+		addAtom(s, p.Type.String())
+	}
+	if p.Optional {
+		addAtom(s, "optional")
+	}
+	if p.Repeated {
+		addAtom(s, "repeated")
+	}
+	if p.Name != "" {
+		name := sexpr.NewSexpr("name")
+		addAtom(name, p.Name)
+		addChild(s, name)
+	}
+	return s
+}
+
+func newAtom(v string) *sexpr.SexprString {
+	return sexpr.NewSexprString(v)
+}
+
+func addAtom(s *sexpr.Sexpr, v string) {
+	param, _ := sexpr.NewSexprParam(newAtom(v))
+	s.AddParam(len(s.Params()), param)
+}
+
+func addChild(s *sexpr.Sexpr, child *sexpr.Sexpr) {
+	param, _ := sexpr.NewSexprParam(child)
+	s.AddParam(len(s.Params()), param)
+}
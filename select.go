@@ -0,0 +1,167 @@
+package sexpr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type selectStep struct {
+	deep    bool
+	name    *NameMatch
+	seg     pathSegment
+	indexed bool
+}
+
+// Select returns the sexprs under root matched by expr, in document order.
+// Expressions extend the paths accepted by Resolve:
+//
+//   - a name is a glob pattern, so * matches any name and fp_* any name
+//     starting with fp_, unless it is quoted as in Path, when it matches
+//     only itself;
+//   - a segment without a selector matches every sexpr with that name, not
+//     just the first;
+//   - // instead of / matches at any depth below the previous segment, e.g.
+//     //pad or /kicad_pcb/footprint//pad;
+//   - [N] selects the N'th matching sibling and [key=value] selects sexprs
+//     whose key child has that value, as in Path and PathKeyed.
+func Select(root *Sexpr, expr string) ([]*Sexpr, error) {
+	steps, err := parseSelect(expr)
+	if err != nil {
+		return nil, err
+	}
+	matches := []*Sexpr{}
+	if root == nil {
+		return matches, nil
+	}
+
+	// the first step is matched against the root itself
+	candidates := []*Sexpr{root}
+	if steps[0].deep {
+		candidates = append(candidates, descendants(root)...)
+	}
+	matches = filterStep(candidates, steps[0])
+	for _, step := range steps[1:] {
+		seen := map[*Sexpr]bool{}
+		next := []*Sexpr{}
+		for _, s := range matches {
+			candidates := directChildren(s)
+			if step.deep {
+				candidates = descendants(s)
+			}
+			for _, c := range filterStep(candidates, step) {
+				if !seen[c] {
+					seen[c] = true
+					next = append(next, c)
+				}
+			}
+		}
+		matches = next
+	}
+	return matches, nil
+}
+
+func filterStep(candidates []*Sexpr, step selectStep) []*Sexpr {
+	matches := []*Sexpr{}
+	for _, s := range candidates {
+		if !step.name.Match(s.Name()) {
+			continue
+		}
+		if step.seg.key != "" {
+			if v, ok := pathKeyValue(s, step.seg.key); !ok || v != step.seg.value {
+				continue
+			}
+		} else if step.indexed && siblingIndex(s, step.name) != step.seg.index {
+			continue
+		}
+		matches = append(matches, s)
+	}
+	return matches
+}
+
+// siblingIndex counts the siblings before s whose names match nm.
+func siblingIndex(s *Sexpr, nm *NameMatch) int {
+	if s.parent == nil {
+		return 0
+	}
+	idx := 0
+	for _, param := range s.parent.params {
+		sibling, ok := param.Value().(*Sexpr)
+		if !ok {
+			continue
+		}
+		if sibling == s {
+			break
+		}
+		if nm.Match(sibling.Name()) {
+			idx += 1
+		}
+	}
+	return idx
+}
+
+func directChildren(s *Sexpr) []*Sexpr {
+	children := []*Sexpr{}
+	for _, param := range s.params {
+		if child, ok := param.Value().(*Sexpr); ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func descendants(s *Sexpr) []*Sexpr {
+	all := []*Sexpr{}
+	for _, child := range directChildren(s) {
+		all = append(all, child)
+		all = append(all, descendants(child)...)
+	}
+	return all
+}
+
+func parseSelect(expr string) ([]selectStep, error) {
+	if !strings.HasPrefix(expr, "/") {
+		return nil, errors.New("expression must start with '/'")
+	}
+	steps := []selectStep{}
+	rest := expr
+	for len(rest) > 0 {
+		if rest[0] != '/' {
+			return nil, fmt.Errorf("invalid expression '%s': expected '/' at offset %d", expr, len(expr)-len(rest))
+		}
+		step := selectStep{}
+		if strings.HasPrefix(rest, "//") {
+			step.deep = true
+			rest = rest[2:]
+		} else {
+			rest = rest[1:]
+		}
+		name, quoted, after, err := parsePathName(rest, "/[")
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression '%s': %s", expr, err.Error())
+		}
+		step.seg.name = name
+		kind := NameMatchGlob
+		if quoted {
+			kind = NameMatchExact
+		}
+		nm, err := NewNameMatch(kind, name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression '%s': %s", expr, err.Error())
+		}
+		step.name = nm
+		rest = after
+		if strings.HasPrefix(rest, "[") {
+			rest, err = parsePathSelector(rest[1:], &step.seg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expression '%s': %s", expr, err.Error())
+			}
+			step.indexed = step.seg.key == ""
+		}
+		steps = append(steps, step)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("invalid expression '%s': no segments", expr)
+	}
+	return steps, nil
+}
@@ -0,0 +1,67 @@
+package sexpr
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(pathTestInput)))
+	require.NoError(t, err)
+
+	paths := func(expr string) []string {
+		matches, err := Select(root, expr)
+		require.NoError(t, err)
+		result := []string{}
+		for _, m := range matches {
+			result = append(result, m.Path())
+		}
+		return result
+	}
+
+	require.Equal(t, []string{"/kicad_pcb"}, paths("/kicad_pcb"))
+	require.Equal(t, []string{}, paths("/other"))
+	require.Equal(t, []string{"/kicad_pcb/footprint[0]", "/kicad_pcb/footprint[1]"}, paths("/kicad_pcb/footprint"))
+	require.Equal(t, []string{"/kicad_pcb/footprint[1]/pad[2]"}, paths("//pad[2]"))
+	require.Equal(t, []string{
+		"/kicad_pcb/footprint[0]/pad[0]",
+		"/kicad_pcb/footprint[1]/pad[0]",
+	}, paths("/kicad_pcb/footprint/pad[0]"))
+	require.Equal(t, 5, len(paths("/kicad_pcb//pad")))
+	require.Equal(t, []string{"/kicad_pcb/footprint[1]/pad[0]"}, paths(`/*/footprint[uuid=b2]/pad[0]`))
+	require.Equal(t, []string{"/kicad_pcb/footprint[0]/uuid[0]"}, paths(`//footprint[uuid="a/1"]//uuid`))
+	require.Equal(t, []string{"/kicad_pcb/net[0]"}, paths("/kicad_pcb/*[0]"))
+	require.Equal(t, []string{"/kicad_pcb/footprint[0]"}, paths("/kicad_pcb/*[1]"))
+	require.Equal(t, []string{"/kicad_pcb/footprint[1]/pad[2]"}, paths("/kicad_pcb/*/p?d[2]"))
+
+	// quoted names match literally
+	quoted := parseString(t, `(root (a* 1) (ab 2))`)
+	matches, err := Select(quoted, `/root/"a*"`)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(matches))
+	require.Equal(t, "a*", matches[0].Name())
+	matches, err = Select(quoted, `/root/a*`)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(matches))
+
+	// overlapping descendant steps report each sexpr once
+	require.Equal(t, 5, len(paths("//kicad_pcb//*//pad")))
+
+	_, err = Select(root, "kicad_pcb")
+	require.ErrorContains(t, err, "must start with")
+
+	_, err = Select(root, "/kicad_pcb///pad")
+	require.ErrorContains(t, err, "empty name")
+
+	_, err = Select(root, "/kicad_pcb/[x")
+	require.ErrorContains(t, err, "empty name")
+
+	_, err = Select(root, "/kicad_pcb/pad[")
+	require.ErrorContains(t, err, "unterminated")
+
+	_, err = Select(root, "/kicad_pcb/[a-")
+	require.Error(t, err)
+}
@@ -9,9 +9,10 @@ type Sexpr struct {
 	name   string
 	params []*SexprParam
 
-	parent *Sexpr
-	line   int
-	col    int
+	parent  *Sexpr
+	line    int
+	col     int
+	version uint64
 }
 
 type FindPredicate func(sexpr *Sexpr, depth int) bool
@@ -26,6 +27,7 @@ func (s *Sexpr) Name() string {
 
 func (s *Sexpr) SetName(name string) {
 	s.name = name
+	s.touch()
 }
 
 func (s *Sexpr) Params() []*SexprParam {
@@ -42,6 +44,8 @@ func (s *Sexpr) AddParam(idx int, param *SexprParam) error {
 		s.params = append(s.params[:idx+1], s.params[idx:]...)
 		s.params[idx] = param
 	}
+	param.SetParent(s)
+	s.touch()
 	return nil
 }
 
@@ -51,6 +55,7 @@ func (s *Sexpr) SetParam(idx int, param *SexprParam) error {
 	}
 	s.params[idx] = param
 	param.SetParent(s)
+	s.touch()
 	return nil
 }
 
@@ -81,6 +86,7 @@ func (s *Sexpr) RemoveParam(idx int, param *SexprParam) error {
 		return errors.New("index out of range")
 	}
 	s.params = append(s.params[:idx], s.params[idx+1:]...)
+	s.touch()
 	return nil
 }
 
@@ -92,6 +98,18 @@ func (s *Sexpr) SetParent(parent *Sexpr) {
 	s.parent = parent
 }
 
+// Version is incremented whenever the sexpr or any of its descendants is
+// modified through this package's setters.
+func (s *Sexpr) Version() uint64 {
+	return s.version
+}
+
+func (s *Sexpr) touch() {
+	for sexpr := s; sexpr != nil; sexpr = sexpr.parent {
+		sexpr.version += 1
+	}
+}
+
 func (s *Sexpr) Location() (int, int) {
 	return s.line, s.col
 }
@@ -101,6 +119,9 @@ func (s *Sexpr) SetLocation(line int, col int) {
 	s.col = col
 }
 
+// String prints s with each child sexpr on its own line, indented by one tab
+// per level, and atoms on the line of the sexpr they belong to. A sexpr
+// without params prints as (name), with no line break of its own.
 func (s *Sexpr) String() string {
 	var sb strings.Builder
 	s.string_(&sb, 0)
@@ -115,7 +136,10 @@ func (s *Sexpr) string_(acc *strings.Builder, level int) {
 	acc.WriteString("(")
 	acc.WriteString(s.Name())
 	if len(s.Params()) == 0 {
-		acc.WriteString(")\n")
+		// the parent writes the line break before each child and before its
+		// own closing paren, so one here would leave a blank line after
+		// every empty child, which KiCad never writes
+		acc.WriteString(")")
 	} else {
 		for _, param := range params {
 			paramv := param.Value()
@@ -141,65 +165,43 @@ func (s *Sexpr) string_(acc *strings.Builder, level int) {
 }
 
 func (s *Sexpr) FindChild(fp FindPredicate, maxDepth int) *Sexpr {
-	queue := NewSexprQueue()
-	queue.Enqueue(s)
-	depth := 1
-
-	for {
-		sexpr := queue.Dequeue()
-		if sexpr == nil {
-			return nil
-		}
-		for _, param := range sexpr.params {
-			if sexpr, ok := param.Value().(*Sexpr); ok {
-				if found := fp(sexpr, depth); found {
-					return sexpr
-				}
-				if maxDepth == -1 || depth < maxDepth {
-					queue.Enqueue(sexpr)
-				}
-			}
-		}
-		depth += 1
-	}
+	sexpr, _ := NewSexprIterator(s, fp, maxDepth, TraversalBreadthFirst).Next()
+	return sexpr
 }
 
 func (s *Sexpr) FindChildren(fp FindPredicate, maxDepth int) []*Sexpr {
-	children := []*Sexpr{}
+	return s.FindChildrenN(fp, maxDepth, -1)
+}
 
-	queue := NewSexprQueue()
-	queue.Enqueue(s)
-	depth := 1
+func (s *Sexpr) FindChildrenN(fp FindPredicate, maxDepth int, limit int) []*Sexpr {
+	children := []*Sexpr{}
+	if limit == 0 {
+		return children
+	}
 
+	it := NewSexprIterator(s, fp, maxDepth, TraversalBreadthFirst)
 	for {
-		sexpr := queue.Dequeue()
+		sexpr, _ := it.Next()
 		if sexpr == nil {
 			return children
 		}
-		for _, param := range sexpr.params {
-			if sexpr, ok := param.Value().(*Sexpr); ok {
-				if found := fp(sexpr, depth); found {
-					children = append(children, sexpr)
-				}
-				if maxDepth == -1 || depth < maxDepth {
-					queue.Enqueue(sexpr)
-				}
-			}
+		children = append(children, sexpr)
+		if limit != -1 && len(children) >= limit {
+			return children
 		}
-		depth += 1
 	}
 }
 
+func (s *Sexpr) FindChildrenIter(fp FindPredicate, maxDepth int, order TraversalOrder) *SexprIterator {
+	return NewSexprIterator(s, fp, maxDepth, order)
+}
+
 func (s *Sexpr) FindChildByName(name string, maxDepth int) *Sexpr {
-	return s.FindChild(func(s *Sexpr, d int) bool {
-		return strings.EqualFold(s.Name(), name)
-	}, maxDepth)
+	return s.FindChildByMatch(&NameMatch{kind: NameMatchFold, pattern: name}, maxDepth)
 }
 
 func (s *Sexpr) FindChildrenByName(name string, maxDepth int) []*Sexpr {
-	return s.FindChildren(func(s *Sexpr, d int) bool {
-		return strings.EqualFold(s.Name(), name)
-	}, maxDepth)
+	return s.FindChildrenByMatch(&NameMatch{kind: NameMatchFold, pattern: name}, maxDepth)
 }
 
 func (s *Sexpr) FindDirectChildByName(name string) *Sexpr {
@@ -209,3 +211,35 @@ func (s *Sexpr) FindDirectChildByName(name string) *Sexpr {
 func (s *Sexpr) FindDirectChildrenByName(name string) []*Sexpr {
 	return s.FindChildrenByName(name, 1)
 }
+
+func (s *Sexpr) FindChildByNameExact(name string, maxDepth int) *Sexpr {
+	return s.FindChildByMatch(&NameMatch{kind: NameMatchExact, pattern: name}, maxDepth)
+}
+
+func (s *Sexpr) FindChildrenByNameExact(name string, maxDepth int) []*Sexpr {
+	return s.FindChildrenByMatch(&NameMatch{kind: NameMatchExact, pattern: name}, maxDepth)
+}
+
+func (s *Sexpr) FindDirectChildByNameExact(name string) *Sexpr {
+	return s.FindChildByNameExact(name, 1)
+}
+
+func (s *Sexpr) FindDirectChildrenByNameExact(name string) []*Sexpr {
+	return s.FindChildrenByNameExact(name, 1)
+}
+
+func (s *Sexpr) FindChildByMatch(nm *NameMatch, maxDepth int) *Sexpr {
+	return s.FindChild(nm.Predicate(), maxDepth)
+}
+
+func (s *Sexpr) FindChildrenByMatch(nm *NameMatch, maxDepth int) []*Sexpr {
+	return s.FindChildren(nm.Predicate(), maxDepth)
+}
+
+func (s *Sexpr) FindDirectChildByMatch(nm *NameMatch) *Sexpr {
+	return s.FindChildByMatch(nm, 1)
+}
+
+func (s *Sexpr) FindDirectChildrenByMatch(nm *NameMatch) []*Sexpr {
+	return s.FindChildrenByMatch(nm, 1)
+}
@@ -0,0 +1,109 @@
+package sexpr
+
+// SexprIterator lazily walks the descendants of a Sexpr. Breadth-first
+// traversal only holds the sexprs of the level being expanded and the next;
+// depth-first traversal only holds the current ancestry.
+type SexprIterator struct {
+	order    TraversalOrder
+	fp       FindPredicate
+	maxDepth int
+
+	// breadth-first
+	queue     *SexprQueue
+	levelLeft int
+
+	// depth-first
+	stack []sexprIteratorFrame
+
+	cur   *Sexpr
+	idx   int
+	depth int
+}
+
+type sexprIteratorFrame struct {
+	sexpr *Sexpr
+	idx   int
+	depth int
+}
+
+func NewSexprIterator(s *Sexpr, fp FindPredicate, maxDepth int, order TraversalOrder) *SexprIterator {
+	it := SexprIterator{order: order, fp: fp, maxDepth: maxDepth}
+	if maxDepth == 0 {
+		return &it
+	}
+	switch order {
+	case TraversalDepthFirst:
+		it.stack = []sexprIteratorFrame{{sexpr: s, idx: 0, depth: 1}}
+	default:
+		it.queue = NewSexprQueue()
+		it.queue.Enqueue(s)
+	}
+	return &it
+}
+
+// Next returns the next matching sexpr and its depth below the starting
+// sexpr, or nil when the traversal is exhausted.
+func (it *SexprIterator) Next() (*Sexpr, int) {
+	if it.order == TraversalDepthFirst {
+		return it.nextDepthFirst()
+	}
+	return it.nextBreadthFirst()
+}
+
+func (it *SexprIterator) nextBreadthFirst() (*Sexpr, int) {
+	if it.queue == nil {
+		return nil, 0
+	}
+	for {
+		if it.cur != nil && it.idx < len(it.cur.params) {
+			param := it.cur.params[it.idx]
+			it.idx += 1
+			sexpr, ok := param.Value().(*Sexpr)
+			if !ok {
+				continue
+			}
+			if it.maxDepth == -1 || it.depth < it.maxDepth {
+				it.queue.Enqueue(sexpr)
+			}
+			if it.fp == nil || it.fp(sexpr, it.depth) {
+				return sexpr, it.depth
+			}
+			continue
+		}
+		if it.levelLeft == 0 {
+			if it.queue.Len() == 0 {
+				it.cur = nil
+				return nil, 0
+			}
+			it.depth += 1
+			it.levelLeft = it.queue.Len()
+		}
+		it.cur = it.queue.Dequeue()
+		it.idx = 0
+		it.levelLeft -= 1
+	}
+}
+
+func (it *SexprIterator) nextDepthFirst() (*Sexpr, int) {
+	for len(it.stack) > 0 {
+		top := &it.stack[len(it.stack)-1]
+		if top.idx >= len(top.sexpr.params) {
+			it.stack = it.stack[:len(it.stack)-1]
+			continue
+		}
+		param := top.sexpr.params[top.idx]
+		top.idx += 1
+		sexpr, ok := param.Value().(*Sexpr)
+		if !ok {
+			continue
+		}
+		depth := top.depth
+		if it.maxDepth == -1 || depth < it.maxDepth {
+			it.stack = append(it.stack, sexprIteratorFrame{sexpr: sexpr, idx: 0, depth: depth + 1})
+		}
+		if it.fp == nil || it.fp(sexpr, depth) {
+			return sexpr, depth
+		}
+	}
+	return nil, 0
+}
@@ -0,0 +1,79 @@
+package sexpr
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func collectNames(it *SexprIterator) ([]string, []int) {
+	names := []string{}
+	depths := []int{}
+	for {
+		sexpr, depth := it.Next()
+		if sexpr == nil {
+			return names, depths
+		}
+		names = append(names, sexpr.Name())
+		depths = append(depths, depth)
+	}
+}
+
+func TestIteratorBreadthFirst(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a (b (d (f))) x (c (e)))`)))
+	require.NoError(t, err)
+
+	names, depths := collectNames(NewSexprIterator(root, nil, -1, TraversalBreadthFirst))
+	require.Equal(t, []string{"b", "c", "d", "e", "f"}, names)
+	require.Equal(t, []int{1, 1, 2, 2, 3}, depths)
+
+	names, _ = collectNames(NewSexprIterator(root, nil, 2, TraversalBreadthFirst))
+	require.Equal(t, []string{"b", "c", "d", "e"}, names)
+
+	names, _ = collectNames(NewSexprIterator(root, nil, 0, TraversalBreadthFirst))
+	require.Equal(t, []string{}, names)
+}
+
+func TestIteratorDepthFirst(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a (b (d (f))) x (c (e)))`)))
+	require.NoError(t, err)
+
+	names, depths := collectNames(root.FindChildrenIter(nil, -1, TraversalDepthFirst))
+	require.Equal(t, []string{"b", "d", "f", "c", "e"}, names)
+	require.Equal(t, []int{1, 2, 3, 1, 2}, depths)
+
+	names, _ = collectNames(root.FindChildrenIter(nil, 1, TraversalDepthFirst))
+	require.Equal(t, []string{"b", "c"}, names)
+
+	names, _ = collectNames(root.FindChildrenIter(GetByNameFindPredicate("e"), -1, TraversalDepthFirst))
+	require.Equal(t, []string{"e"}, names)
+}
+
+func TestIteratorDepthIsPerLevel(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a (b (x)) (c (x)) (d (x)))`)))
+	require.NoError(t, err)
+
+	sexprs := root.FindChildren(func(s *Sexpr, depth int) bool {
+		return s.Name() == "x" && depth == 2
+	}, -1)
+	require.Equal(t, 3, len(sexprs))
+
+	require.Equal(t, 3, len(root.FindChildrenByName("x", 2)))
+	require.Equal(t, 0, len(root.FindChildrenByName("x", 1)))
+}
+
+func TestFindChildrenN(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(a (p 1) (p 2) (b (p 3)) (p 4))`)))
+	require.NoError(t, err)
+
+	sexprs := root.FindChildrenN(GetByNameFindPredicate("p"), -1, 2)
+	require.Equal(t, 2, len(sexprs))
+	require.Equal(t, "1", sexprs[0].Params()[0].String())
+	require.Equal(t, "2", sexprs[1].Params()[0].String())
+
+	require.Equal(t, 4, len(root.FindChildrenN(GetByNameFindPredicate("p"), -1, -1)))
+	require.Equal(t, 4, len(root.FindChildrenN(GetByNameFindPredicate("p"), -1, 10)))
+	require.Equal(t, 0, len(root.FindChildrenN(GetByNameFindPredicate("p"), -1, 0)))
+}
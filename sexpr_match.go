@@ -0,0 +1,75 @@
+package sexpr
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+type NameMatch struct {
+	kind    NameMatchKind
+	pattern string
+	re      *regexp.Regexp
+}
+
+func NewNameMatch(kind NameMatchKind, pattern string) (*NameMatch, error) {
+	nm := NameMatch{kind: kind, pattern: pattern}
+	switch kind {
+	case NameMatchFold, NameMatchExact:
+	case NameMatchGlob:
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, err
+		}
+	case NameMatchGlobFold:
+		nm.pattern = strings.ToLower(pattern)
+		if _, err := path.Match(nm.pattern, ""); err != nil {
+			return nil, err
+		}
+	case NameMatchRegexp:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		nm.re = re
+	default:
+		return nil, errors.New("unknown name match kind")
+	}
+	return &nm, nil
+}
+
+func NewNameMatchRegexp(re *regexp.Regexp) *NameMatch {
+	return &NameMatch{kind: NameMatchRegexp, pattern: re.String(), re: re}
+}
+
+func (nm *NameMatch) Kind() NameMatchKind {
+	return nm.kind
+}
+
+func (nm *NameMatch) Pattern() string {
+	return nm.pattern
+}
+
+func (nm *NameMatch) Match(name string) bool {
+	switch nm.kind {
+	case NameMatchFold:
+		return strings.EqualFold(name, nm.pattern)
+	case NameMatchExact:
+		return name == nm.pattern
+	case NameMatchGlob:
+		ok, _ := path.Match(nm.pattern, name)
+		return ok
+	case NameMatchGlobFold:
+		ok, _ := path.Match(nm.pattern, strings.ToLower(name))
+		return ok
+	case NameMatchRegexp:
+		return nm.re.MatchString(name)
+	}
+	return false
+}
+
+func (nm *NameMatch) Predicate() FindPredicate {
+	return func(sexpr *Sexpr, depth int) bool {
+		return nm.Match(sexpr.Name())
+	}
+}
@@ -0,0 +1,11 @@
+package sexpr
+
+type NameMatchKind int
+
+const (
+	NameMatchFold NameMatchKind = iota
+	NameMatchExact
+	NameMatchGlob
+	NameMatchGlobFold
+	NameMatchRegexp
+)
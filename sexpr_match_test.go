@@ -0,0 +1,78 @@
+package sexpr
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameMatch(t *testing.T) {
+	nm, err := NewNameMatch(NameMatchFold, "Reference")
+	require.NoError(t, err)
+	require.True(t, nm.Match("reference"))
+	require.True(t, nm.Match("REFERENCE"))
+
+	nm, err = NewNameMatch(NameMatchExact, "Reference")
+	require.NoError(t, err)
+	require.True(t, nm.Match("Reference"))
+	require.False(t, nm.Match("reference"))
+
+	nm, err = NewNameMatch(NameMatchGlob, "fp_*")
+	require.NoError(t, err)
+	require.True(t, nm.Match("fp_line"))
+	require.False(t, nm.Match("FP_line"))
+	require.False(t, nm.Match("gr_line"))
+
+	nm, err = NewNameMatch(NameMatchGlobFold, "FP_*")
+	require.NoError(t, err)
+	require.True(t, nm.Match("fp_line"))
+
+	nm, err = NewNameMatch(NameMatchRegexp, "^(fp|gr)_(line|arc)$")
+	require.NoError(t, err)
+	require.True(t, nm.Match("gr_arc"))
+	require.False(t, nm.Match("gr_circle"))
+
+	_, err = NewNameMatch(NameMatchGlob, "[")
+	require.Error(t, err)
+
+	_, err = NewNameMatch(NameMatchRegexp, "(")
+	require.Error(t, err)
+}
+
+func TestFindByNameExact(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(aaa (Property a) (property b) (ddd (property c)))`)))
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(root.FindChildrenByName("property", -1)))
+
+	sexprs := root.FindChildrenByNameExact("property", -1)
+	require.Equal(t, 2, len(sexprs))
+	require.Equal(t, "b", sexprs[0].Params()[0].String())
+	require.Equal(t, "c", sexprs[1].Params()[0].String())
+
+	require.Equal(t, 1, len(root.FindDirectChildrenByNameExact("property")))
+	require.Equal(t, "Property", root.FindDirectChildByNameExact("Property").Name())
+	require.Nil(t, root.FindDirectChildByNameExact("PROPERTY"))
+	require.Nil(t, root.FindChildByNameExact("DDD", -1))
+}
+
+func TestFindByMatch(t *testing.T) {
+	root, err := Parse(bufio.NewReader(strings.NewReader(`(footprint (fp_line a) (fp_arc b) (pad (fp_text c)) (gr_line d))`)))
+	require.NoError(t, err)
+
+	nm, err := NewNameMatch(NameMatchGlob, "fp_*")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(root.FindChildrenByMatch(nm, -1)))
+	require.Equal(t, 2, len(root.FindDirectChildrenByMatch(nm)))
+	require.Equal(t, "fp_line", root.FindDirectChildByMatch(nm).Name())
+
+	nm = NewNameMatchRegexp(regexp.MustCompile(`_line$`))
+	sexprs := root.FindChildrenByMatch(nm, -1)
+	require.Equal(t, 2, len(sexprs))
+	require.Equal(t, "fp_line", sexprs[0].Name())
+	require.Equal(t, "gr_line", sexprs[1].Name())
+	require.Equal(t, "fp_line", root.FindChildByMatch(nm, -1).Name())
+}
@@ -32,6 +32,7 @@ func (sp *SexprParam) Value() any {
 }
 
 func (sp *SexprParam) SetValue(v any) error {
+	parent := sp.Parent()
 	switch v.(type) {
 	case *SexprString:
 		sp.value = v
@@ -42,6 +43,10 @@ func (sp *SexprParam) SetValue(v any) error {
 	default:
 		return errors.New("value must be string or sexpr")
 	}
+	if parent != nil {
+		sp.SetParent(parent)
+		parent.touch()
+	}
 	return nil
 }
 
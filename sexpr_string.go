@@ -32,11 +32,13 @@ func (ss *SexprString) Value() string {
 func (ss *SexprString) SetValue(v string) {
 	ss.value = v
 	ss.quoted = shouldQuote(v)
+	ss.touch()
 }
 
 func (ss *SexprString) SetValueQuoted(v string, quoted bool) {
 	ss.value = v
 	ss.quoted = quoted
+	ss.touch()
 }
 
 func (ss *SexprString) Quoted() bool {
@@ -51,6 +53,12 @@ func (ss *SexprString) SetParent(parent *Sexpr) {
 	ss.parent = parent
 }
 
+func (ss *SexprString) touch() {
+	if ss.parent != nil {
+		ss.parent.touch()
+	}
+}
+
 func (ss *SexprString) Location() (int, int) {
 	return ss.line, ss.col
 }
@@ -0,0 +1,169 @@
+package sexpr
+
+import (
+	"sort"
+	"strconv"
+)
+
+// SortKey says how to order two sexprs. Build one with SortByParam,
+// SortByChild or SortByFunc.
+type SortKey struct {
+	kind    SortKeyKind
+	index   int
+	names   []string
+	compare func(a *Sexpr, b *Sexpr) int
+}
+
+// SortByParam orders sexprs by their idx'th string param.
+func SortByParam(idx int) SortKey {
+	return SortKey{kind: SortKeyParam, index: idx}
+}
+
+// SortByChild orders sexprs by the idx'th string param of their first
+// direct child named one of names, e.g. SortByChild(0, "uuid", "tstamp").
+func SortByChild(idx int, names ...string) SortKey {
+	return SortKey{kind: SortKeyChild, index: idx, names: names}
+}
+
+// SortByFunc orders sexprs with compare, which returns a negative number
+// when a sorts before b, a positive one when after and 0 when either order
+// will do.
+func SortByFunc(compare func(a *Sexpr, b *Sexpr) int) SortKey {
+	return SortKey{kind: SortKeyFunc, compare: compare}
+}
+
+func (k SortKey) Kind() SortKeyKind {
+	return k.kind
+}
+
+// SortRule sorts the children of sexprs named Parent, or of every sexpr
+// when Parent is "*". Only children named in Children are moved, among the
+// positions they already take, or all children when Children is empty.
+type SortRule struct {
+	Parent   string
+	Children []string
+	Key      SortKey
+}
+
+// Sort applies rules to root and all its descendants. Sorting is stable,
+// and sexprs without a key value sort before those with one. Values that
+// are both decimal numbers compare numerically, others as strings. When
+// several rules apply to a sexpr they run in order.
+func Sort(root *Sexpr, rules []SortRule) {
+	for _, rule := range rules {
+		if rule.Parent == "*" || rule.Parent == root.name {
+			root.sortChildren(rule)
+		}
+	}
+	for _, param := range root.params {
+		if child, ok := param.Value().(*Sexpr); ok {
+			Sort(child, rules)
+		}
+	}
+}
+
+func (s *Sexpr) sortChildren(rule SortRule) {
+	slots := []*SexprParam{}
+	children := []*Sexpr{}
+	for _, param := range s.params {
+		child, ok := param.Value().(*Sexpr)
+		if !ok || (len(rule.Children) > 0 && !containsName(rule.Children, child.name)) {
+			continue
+		}
+		slots = append(slots, param)
+		children = append(children, child)
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return rule.Key.compareSexprs(children[i], children[j]) < 0
+	})
+	changed := false
+	for i, slot := range slots {
+		if slot.value != children[i] {
+			slot.value = children[i]
+			changed = true
+		}
+	}
+	if changed {
+		s.touch()
+	}
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (k SortKey) compareSexprs(a *Sexpr, b *Sexpr) int {
+	if k.kind == SortKeyFunc {
+		return k.compare(a, b)
+	}
+	av, aok := k.value(a)
+	bv, bok := k.value(b)
+	switch {
+	case !aok && !bok:
+		return 0
+	case !aok:
+		return -1
+	case !bok:
+		return 1
+	}
+	return compareValues(av, bv)
+}
+
+func (k SortKey) value(s *Sexpr) (string, bool) {
+	if k.kind == SortKeyChild {
+		var child *Sexpr
+		for _, name := range k.names {
+			if child = s.FindDirectChildByNameExact(name); child != nil {
+				break
+			}
+		}
+		if child == nil {
+			return "", false
+		}
+		s = child
+	}
+	n := 0
+	for _, param := range s.params {
+		if ss, ok := param.Value().(*SexprString); ok {
+			if n == k.index {
+				return ss.value, true
+			}
+			n += 1
+		}
+	}
+	return "", false
+}
+
+// compareValues compares numbers numerically, then numbers before other
+// strings, then strings bytewise. Only decimal literals are numbers, not
+// spellings such as nan or inf that ParseFloat also accepts.
+func compareValues(a string, b string) int {
+	anum, bnum := numberPattern.MatchString(a), numberPattern.MatchString(b)
+	switch {
+	case anum && bnum:
+		// out of range values parse as infinities, which still order
+		af, _ := strconv.ParseFloat(a, 64)
+		bf, _ := strconv.ParseFloat(b, 64)
+		if af < bf {
+			return -1
+		} else if af > bf {
+			return 1
+		}
+		return 0
+	case anum:
+		return -1
+	case bnum:
+		return 1
+	}
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,9 @@
+package sexpr
+
+type SortKeyKind int
+
+const (
+	SortKeyParam SortKeyKind = iota
+	SortKeyChild
+	SortKeyFunc
+)
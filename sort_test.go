@@ -0,0 +1,77 @@
+package sexpr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func names(s *Sexpr) string {
+	result := []string{}
+	for _, param := range s.Params() {
+		if child, ok := param.Value().(*Sexpr); ok && len(child.Params()) == 0 {
+			result = append(result, child.Name())
+		} else if ok {
+			result = append(result, child.Name()+":"+child.Params()[0].String())
+		} else {
+			result = append(result, param.String())
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+func TestSortByParam(t *testing.T) {
+	root := parseString(t, `(kicad_pcb (version 1) (net 10 a) (net 2 b) hide (net x c) (net 1 d) (footprint 2) (net))`)
+	version := root.Version()
+	Sort(root, []SortRule{{Parent: "kicad_pcb", Children: []string{"net"}, Key: SortByParam(0)}})
+	require.NotEqual(t, version, root.Version())
+
+	// nets move among their own positions, other params stay put, and nets
+	// without the key sort first
+	require.Equal(t, "version:1 net net:1 hide net:2 net:10 footprint:2 net:x", names(root))
+	for _, net := range root.FindDirectChildrenByNameExact("net") {
+		require.Same(t, root, net.Parent())
+	}
+
+	// only decimal literals compare as numbers
+	root = parseString(t, `(lib (x nan) (x 2) (x 1) (x NaN) (x 0) (x inf) (x -1e500))`)
+	Sort(root, []SortRule{{Parent: "lib", Key: SortByParam(0)}})
+	require.Equal(t, "x:-1e500 x:0 x:1 x:2 x:NaN x:inf x:nan", names(root))
+
+	root = parseString(t, `(lib (net 1 b) (net 2 a))`)
+	Sort(root, []SortRule{{Parent: "lib", Key: SortByParam(1)}})
+	require.Equal(t, "net:2 net:1", names(root))
+}
+
+func TestSortByChild(t *testing.T) {
+	root := parseString(t, `(kicad_pcb
+		(footprint R2 (uuid "c") (pad 2) (pad 1))
+		(footprint R1 (tstamp "b") (pad 3) (pad 10))
+		(footprint R3 (uuid "a"))
+		(footprint R0)
+	)`)
+	Sort(root, []SortRule{
+		{Parent: "kicad_pcb", Children: []string{"footprint"}, Key: SortByChild(0, "uuid", "tstamp")},
+		{Parent: "footprint", Children: []string{"pad"}, Key: SortByParam(0)},
+	})
+	require.Equal(t, "footprint:R0 footprint:R3 footprint:R1 footprint:R2", names(root))
+
+	// rules apply recursively
+	require.Equal(t, `R2 uuid:"c" pad:1 pad:2`, names(root.FindDirectChildrenByNameExact("footprint")[3]))
+	require.Equal(t, `R1 tstamp:"b" pad:3 pad:10`, names(root.FindDirectChildrenByNameExact("footprint")[2]))
+}
+
+func TestSortByFunc(t *testing.T) {
+	root := parseString(t, `(a (b (y 1) (z 1)) (c (w 1) (x 1)))`)
+	byName := SortByFunc(func(a *Sexpr, b *Sexpr) int {
+		return strings.Compare(b.Name(), a.Name())
+	})
+	require.Equal(t, SortKeyFunc, byName.Kind())
+	Sort(root, []SortRule{{Parent: "*", Key: byName}})
+	require.Equal(t, "(a (c (x 1) (w 1)) (b (z 1) (y 1)))", string(root.MarshalCanonical(nil)))
+
+	version := root.Version()
+	Sort(root, []SortRule{{Parent: "*", Key: byName}})
+	require.Equal(t, version, root.Version())
+}
@@ -0,0 +1,8 @@
+package sexpr
+
+type TraversalOrder int
+
+const (
+	TraversalBreadthFirst TraversalOrder = iota
+	TraversalDepthFirst
+)